@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/kevinsapp/monarch/pkg/sqlt"
@@ -17,7 +18,7 @@ func init() {
 
 // addColumnCmd generates a migration file to add a column to a table.
 var addColumnCmd = &cobra.Command{
-	Use:   "column [tableName] [ [colName:type] ... ]",
+	Use:   "column [tableName] [ [colName:type[:comment]] ... ]",
 	Short: "Generate a migration file to add a column named [colName] with type [type].",
 	RunE:  addColumnMigration,
 }
@@ -61,11 +62,10 @@ func addColumnMigration(cmd *cobra.Command, args []string) error {
 
 	// Add columns to table object
 	for _, v := range args[1:] {
-		nameType := strings.Split(v, ":")
-
-		col := sqlt.Column{}
-		col.SetName(nameType[0])
-		col.SetType(nameType[1])
+		col, err := parseColumnSpec(v)
+		if err != nil {
+			return err
+		}
 
 		t.AddColumn(col)
 	}
@@ -83,7 +83,7 @@ func addColumnMigration(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create migration file.
-	err = createMigration("AddColumnsTo_"+tableName, upSQL, downSQL)
+	err = createMigration("AddColumnsTo_"+t.Name(), upSQL, downSQL)
 	if err != nil {
 		return err
 	}
@@ -124,7 +124,7 @@ func dropColumnMigration(cmd *cobra.Command, args []string) error {
 	// }
 
 	// Create migration file.
-	err = createMigration("DropColumnsFrom_"+tableName, upSQL, "")
+	err = createMigration("DropColumnsFrom_"+t.Name(), upSQL, "")
 	if err != nil {
 		return err
 	}
@@ -145,11 +145,10 @@ func recastColumnMigration(cmd *cobra.Command, args []string) error {
 
 	// Add columns to table object
 	for _, v := range args[1:] {
-		nameType := strings.Split(v, ":")
-
-		col := sqlt.Column{}
-		col.SetName(nameType[0])
-		col.SetType(nameType[1])
+		col, err := parseColumnSpec(v)
+		if err != nil {
+			return err
+		}
 
 		t.AddColumn(col)
 	}
@@ -161,7 +160,7 @@ func recastColumnMigration(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create migration file.
-	err = createMigration("RecastColumnsIn_"+tableName, upSQL, "")
+	err = createMigration("RecastColumnsIn_"+t.Name(), upSQL, "")
 	if err != nil {
 		return err
 	}
@@ -219,10 +218,41 @@ func renameColumnMigration(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create migration file.
-	err = createMigration("RenameColumnsIn_"+tableName, upSQL, downSQL)
+	err = createMigration("RenameColumnsIn_"+t.Name(), upSQL, downSQL)
 	if err != nil {
 		return err
 	}
 
 	return err
 }
+
+// parseColumnSpec parses a column argument of the form "colName:type", or
+// "colName:type:comment" for a column with a comment. A type of the form
+// "enum(name)" references an enum type created by "create enum", and may be
+// followed by array brackets, e.g. "enum(name)[]".
+func parseColumnSpec(spec string) (sqlt.Column, error) {
+	col := sqlt.Column{}
+
+	nameType := strings.SplitN(spec, ":", 3)
+	if len(nameType) < 2 || nameType[0] == "" || nameType[1] == "" {
+		return col, fmt.Errorf("column %q should have the form colName:type", spec)
+	}
+	if len(nameType) == 3 {
+		col.SetComment(nameType[2])
+	}
+	col.SetName(nameType[0])
+
+	colType := nameType[1]
+	if strings.HasPrefix(strings.ToLower(colType), "enum(") {
+		end := strings.IndexByte(colType, ')')
+		if end < 0 {
+			return col, fmt.Errorf("column %q has an unterminated enum type", spec)
+		}
+		e := sqlt.Enum{}
+		e.SetName(colType[len("enum("):end])
+		colType = e.Name() + colType[end+1:]
+	}
+	col.SetType(colType)
+
+	return col, nil
+}
@@ -244,3 +244,36 @@ func TestRenameColumnMigrations(t *testing.T) {
 		t.Errorf("\nwant %q;\n got %q\n", exp, act)
 	}
 }
+
+// Unit test parseColumnSpec()
+func TestParseColumnSpec(t *testing.T) {
+	cases := [][]string{
+		{"givenName:varchar", "given_name", "varchar", ""},
+		{"price:numeric(10,2)", "price", "numeric(10,2)", ""},
+		{"status:enum(OrderStatus)", "status", "order_status", ""},
+		{"statuses:ENUM(OrderStatus)[]", "statuses", "order_status[]", ""},
+		{"email:citext:Login name: unique", "email", "citext", "Login name: unique"},
+	}
+	for _, c := range cases {
+		col, err := parseColumnSpec(c[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp, act := c[1], col.Name(); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+		if exp, act := c[2], col.Type(); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+		if exp, act := c[3], col.Comment(); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+	}
+
+	for _, v := range []string{"givenName", "givenName:", "status:enum(OrderStatus"} {
+		_, err := parseColumnSpec(v)
+		if err == nil {
+			t.Errorf("%q: want error; got nil", v)
+		}
+	}
+}
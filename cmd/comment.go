@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	migrationCmd.AddCommand(commentCmd)
+	commentCmd.AddCommand(commentTableCmd)
+	commentCmd.AddCommand(commentColumnCmd)
+}
+
+// commentCmd ...
+var commentCmd = &cobra.Command{
+	Use: "comment",
+}
+
+// commentTableCmd generates a migration file to set the comment on a table.
+var commentTableCmd = &cobra.Command{
+	Use:   "table [tableName] [comment]",
+	Short: "Generate a migration file to set the comment on a table.",
+	Long: `Generate a migration file to set the comment on a table, or to remove it if [comment] is
+	omitted. The "down" migration restores the previous comment, which is read from the
+	development database if it can be reached; otherwise it removes the comment.`,
+	RunE: commentTableMigration,
+}
+
+// commentColumnCmd generates a migration file to set the comment on a column.
+var commentColumnCmd = &cobra.Command{
+	Use:   "column [tableName] [colName] [comment]",
+	Short: "Generate a migration file to set the comment on a column.",
+	Long: `Generate a migration file to set the comment on a column, or to remove it if [comment] is
+	omitted. The "down" migration restores the previous comment, which is read from the
+	development database if it can be reached; otherwise it removes the comment.`,
+	RunE: commentColumnMigration,
+}
+
+// commentTableMigration creates a migration file to set the comment on a
+// table.
+func commentTableMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a table name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a tableName argument")
+	}
+
+	// Set comment data.
+	c := new(sqlt.Comment)
+	c.SetTableName(args[0])
+	if len(args) > 1 {
+		c.SetText(args[1])
+	}
+
+	return createCommentMigration(c, "CommentOnTable_"+c.TableName())
+}
+
+// commentColumnMigration creates a migration file to set the comment on a
+// column.
+func commentColumnMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a table name and a column name.
+	if len(args) < 2 {
+		return errors.New("requires tableName and colName arguments")
+	}
+
+	// Set comment data.
+	c := new(sqlt.Comment)
+	c.SetTableName(args[0])
+	c.SetColumnName(args[1])
+	if len(args) > 2 {
+		c.SetText(args[2])
+	}
+
+	return createCommentMigration(c, "CommentOnColumn_"+c.TableName()+"_"+c.ColumnName())
+}
+
+// createCommentMigration creates a migration file to set a comment. The "down"
+// migration restores the previous comment.
+func createCommentMigration(c *sqlt.Comment, name string) error {
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(c, sqlt.CommentTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	prev := *c
+	text, err := commentFromDB(c)
+	if err != nil {
+		return err
+	}
+	prev.SetText(text)
+	downSQL, err := sqlt.ProcessTmpl(&prev, sqlt.CommentTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration(name, upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// commentFromDB queries the development database for the current comment on
+// a table or column. If no database is configured or it cannot be reached, or
+// there is no comment, commentFromDB returns an empty string.
+func commentFromDB(c *sqlt.Comment) (string, error) {
+	ctx := context.Background()
+	conn := connectDevelopmentDB(ctx)
+	if conn == nil {
+		return "", nil
+	}
+	defer conn.Close(ctx)
+
+	query := `SELECT obj_description(to_regclass($1), 'pg_class');`
+	args := []interface{}{c.TableName()}
+	if c.ColumnName() != "" {
+		query = `SELECT col_description(attrelid, attnum) FROM pg_attribute
+	WHERE attrelid = to_regclass($1) AND attname = $2;`
+		args = append(args, c.ColumnName())
+	}
+
+	var text *string
+	err := conn.QueryRow(ctx, query, args...).Scan(&text)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil || text == nil {
+		return "", err
+	}
+
+	return *text, nil
+}
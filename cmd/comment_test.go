@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// Unit test commentTableMigration() and commentColumnMigration()
+func TestCommentMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+
+	err := commentTableMigration(cmd, []string{"users", "People who can log in"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = commentColumnMigration(cmd, []string{"users", "givenName", "The user's first name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = commentColumnMigration(cmd, []string{"users", "givenName"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(3, t)
+
+	cases := [][]string{
+		{"COMMENT ON TABLE users IS 'People who can log in';", ms[0].UpSQL()},
+		{"COMMENT ON TABLE users IS NULL;", ms[0].DownSQL()},
+		{"COMMENT ON COLUMN users.given_name IS 'The user''s first name';", ms[1].UpSQL()},
+		{"COMMENT ON COLUMN users.given_name IS NULL;", ms[1].DownSQL()},
+		{"COMMENT ON COLUMN users.given_name IS NULL;", ms[2].UpSQL()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+}
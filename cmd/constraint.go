@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+)
+
+// Constraint options set by command flags.
+var (
+	constraintMethod string
+	constraintOnline bool
+)
+
+func init() {
+	addCmd.AddCommand(addConstraintCmd)
+	dropCmd.AddCommand(dropConstraintCmd)
+
+	addConstraintCmd.Flags().StringVar(&constraintMethod, "using", "gist", "index access method for an EXCLUDE constraint")
+	dropConstraintCmd.Flags().StringVar(&constraintMethod, "using", "gist", "index access method for an EXCLUDE constraint")
+	addConstraintCmd.Flags().BoolVar(&constraintOnline, "online", false, "add a CHECK constraint as NOT VALID and validate it in a separate migration")
+}
+
+// addConstraintCmd generates a migration file to add a CHECK, UNIQUE or EXCLUDE constraint to a table.
+var addConstraintCmd = &cobra.Command{
+	Use:   "constraint [tableName] [check|unique|exclude] [ [arg] ... ]",
+	Short: "Generate a migration file to add a CHECK, UNIQUE or EXCLUDE constraint to a table.",
+	Long: `Generate a migration file to add a CHECK, UNIQUE or EXCLUDE constraint to a table.
+	The arguments that follow the kind depend on the kind of constraint:
+
+	  check [name] [expression]
+	  unique [ [colName] ... ]
+	  exclude [ [colName:operator] ... ]
+
+	An EXCLUDE constraint uses the gist access method unless --using is given. Note that
+	the btree_gist extension is required to use scalar equality (=) in a gist EXCLUDE constraint.
+
+	With --online, a CHECK constraint is added as NOT VALID so that existing rows are not
+	scanned while the table is locked, and a second migration validates the constraint
+	in its own transaction.`,
+	RunE: addConstraintMigration,
+}
+
+// dropConstraintCmd generates a migration file to drop a CHECK, UNIQUE or EXCLUDE constraint from a table.
+var dropConstraintCmd = &cobra.Command{
+	Use:   "constraint [tableName] [check|unique|exclude] [ [arg] ... ]",
+	Short: "Generate a migration file to drop a CHECK, UNIQUE or EXCLUDE constraint from a table.",
+	Long: `Generate a migration file to drop a CHECK, UNIQUE or EXCLUDE constraint from a table.
+	The arguments should match those used to add the constraint so that the "down" migration
+	can restore it.`,
+	RunE: dropConstraintMigration,
+}
+
+// addConstraintMigration creates a migration file to add a constraint to a table.
+func addConstraintMigration(cmd *cobra.Command, args []string) error {
+	// Set constraint data.
+	c, err := constraintFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Only CHECK constraints (and foreign keys) can be added as NOT VALID.
+	if constraintOnline && c.Kind() != sqlt.CheckConstraint {
+		return fmt.Errorf("--online is not supported for %s constraints", c.Kind())
+	}
+	c.SetNotValid(constraintOnline)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(c, sqlt.AddConstraintTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(c, sqlt.DropConstraintTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("AddConstraintTo_"+c.TableName(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	// Online: create a second migration to validate the constraint.
+	if constraintOnline {
+		validateSQL, err := sqlt.ProcessTmpl(c, sqlt.ValidateConstraintTmpl)
+		if err != nil {
+			return err
+		}
+
+		name := "ValidateConstraintOn_" + c.TableName()
+		err = createMigrationTx(name, validateSQL, "", migration.TransactionIsolated)
+		if err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// dropConstraintMigration creates a migration file to drop a constraint from a table.
+func dropConstraintMigration(cmd *cobra.Command, args []string) error {
+	// Set constraint data.
+	c, err := constraintFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(c, sqlt.DropConstraintTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(c, sqlt.AddConstraintTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("DropConstraintFrom_"+c.TableName(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// constraintFromArgs configures a constraint from command arguments. The
+// caller should supply a table name as the first argument and a constraint
+// kind as the second argument, followed by arguments specific to that kind.
+func constraintFromArgs(args []string) (*sqlt.Constraint, error) {
+	if len(args) < 3 {
+		return nil, errors.New("requires tableName and kind arguments followed by the constraint definition")
+	}
+
+	c := new(sqlt.Constraint)
+	c.SetTableName(args[0])
+	c.SetKind(args[1])
+
+	switch c.Kind() {
+	case sqlt.CheckConstraint:
+		// A CHECK constraint is named by the caller; the expression may be
+		// supplied as one quoted argument or several unquoted ones.
+		if len(args) < 4 {
+			return nil, errors.New("check requires name and expression arguments")
+		}
+		c.SetName(args[2])
+		c.SetExpression(strings.Join(args[3:], " "))
+	case sqlt.UniqueConstraint:
+		for _, v := range args[2:] {
+			c.AddColumn(v)
+		}
+	case sqlt.ExcludeConstraint:
+		c.SetMethod(constraintMethod)
+		for _, v := range args[2:] {
+			colOp := strings.SplitN(v, ":", 2)
+			if len(colOp) < 2 || colOp[1] == "" {
+				return nil, fmt.Errorf("exclude element %q should have the form colName:operator", v)
+			}
+
+			e := sqlt.ExcludeElement{}
+			e.SetColumn(colOp[0])
+			e.SetOperator(colOp[1])
+
+			c.AddElement(e)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported constraint kind %q: want check, unique or exclude", args[1])
+	}
+
+	return c, nil
+}
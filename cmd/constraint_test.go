@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/spf13/cobra"
+)
+
+// Test data - expected SQL
+const (
+	testAddCheckConstraintSQL string = `ALTER TABLE users
+	ADD CONSTRAINT users_adult_age_mnrk_ckc CHECK (age >= 18);`
+
+	testDropCheckConstraintSQL string = `ALTER TABLE users
+	DROP CONSTRAINT IF EXISTS users_adult_age_mnrk_ckc;`
+
+	testAddUniqueConstraintSQL string = `ALTER TABLE users
+	ADD CONSTRAINT users_account_id_email_mnrk_uqc UNIQUE (account_id, email);`
+
+	testDropUniqueConstraintSQL string = `ALTER TABLE users
+	DROP CONSTRAINT IF EXISTS users_account_id_email_mnrk_uqc;`
+
+	testAddExcludeConstraintSQL string = `ALTER TABLE bookings
+	ADD CONSTRAINT bookings_room_id_during_mnrk_exc EXCLUDE USING gist (room_id WITH =, during WITH &&);`
+
+	testDropExcludeConstraintSQL string = `ALTER TABLE bookings
+	DROP CONSTRAINT IF EXISTS bookings_room_id_during_mnrk_exc;`
+)
+
+// Unit test addConstraintMigration()
+func TestAddConstraintMigration(t *testing.T) {
+	cases := []struct {
+		args    []string
+		upSQL   string
+		downSQL string
+	}{
+		{[]string{"users", "check", "adultAge", "age >= 18"}, testAddCheckConstraintSQL, testDropCheckConstraintSQL},
+		{[]string{"users", "unique", "accountID", "email"}, testAddUniqueConstraintSQL, testDropUniqueConstraintSQL},
+		{[]string{"bookings", "exclude", "roomID:=", "during:&&"}, testAddExcludeConstraintSQL, testDropExcludeConstraintSQL},
+	}
+
+	for _, c := range cases {
+		// Create a migrations directory.
+		cmd := &cobra.Command{}
+		mkdirMigrations(cmd, nil)
+
+		// Run addConstraintMigration()
+		err := addConstraintMigration(cmd, c.args)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := readMigrationsHelper(1, t)[0]
+
+		// Verify that the upSQL is as expected.
+		if exp, act := c.upSQL, m.UpSQL(); exp != act {
+			t.Errorf("\nwant %q;\n got %q\n", exp, act)
+		}
+
+		// Verify that the downSQL is as expected.
+		if exp, act := c.downSQL, m.DownSQL(); exp != act {
+			t.Errorf("\nwant %q;\n got %q\n", exp, act)
+		}
+
+		os.RemoveAll(migrationsDir) // Do cleanup
+	}
+}
+
+// Unit test addConstraintMigration() with --online.
+func TestAddConstraintMigrationOnline(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+
+	// Run addConstraintMigration() with --online.
+	constraintOnline = true
+	defer func() { constraintOnline = false }()
+	args := []string{"users", "check", "adultAge", "age >= 18"}
+	err := addConstraintMigration(cmd, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(2, t)
+
+	// Verify the NOT VALID migration.
+	exp := `ALTER TABLE users
+	ADD CONSTRAINT users_adult_age_mnrk_ckc CHECK (age >= 18) NOT VALID;`
+	if act := ms[0].UpSQL(); exp != act {
+		t.Errorf("\nwant %q;\n got %q\n", exp, act)
+	}
+
+	// Verify the VALIDATE CONSTRAINT migration runs in its own transaction.
+	exp = `ALTER TABLE users
+	VALIDATE CONSTRAINT users_adult_age_mnrk_ckc;`
+	if act := ms[1].UpSQL(); exp != act {
+		t.Errorf("\nwant %q;\n got %q\n", exp, act)
+	}
+	if exp, act := migration.TransactionIsolated, ms[1].Transaction(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	// Verify that --online is rejected for a UNIQUE constraint.
+	err = addConstraintMigration(cmd, []string{"users", "unique", "email"})
+	if err == nil {
+		t.Error("want error; got nil")
+	}
+}
+
+// Unit test dropConstraintMigration()
+func TestDropConstraintMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+
+	// Run dropConstraintMigration()
+	args := []string{"users", "unique", "accountID", "email"}
+	err := dropConstraintMigration(cmd, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := readMigrationsHelper(1, t)[0]
+
+	// Verify that the upSQL is as expected.
+	if exp, act := testDropUniqueConstraintSQL, m.UpSQL(); exp != act {
+		t.Errorf("\nwant %q;\n got %q\n", exp, act)
+	}
+
+	// Verify that the downSQL restores the constraint.
+	if exp, act := testAddUniqueConstraintSQL, m.DownSQL(); exp != act {
+		t.Errorf("\nwant %q;\n got %q\n", exp, act)
+	}
+}
+
+// Unit test constraintFromArgs() with invalid arguments.
+func TestConstraintFromArgsErrors(t *testing.T) {
+	cases := [][]string{
+		{"users"},
+		{"users", "check", "adult_age"},
+		{"users", "primary", "id"},
+		{"bookings", "exclude", "room_id"},
+	}
+	for _, c := range cases {
+		_, err := constraintFromArgs(c)
+		if err == nil {
+			t.Errorf("want error for args %q; got nil", c)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+)
+
+// Copy options set by command flags.
+var (
+	copyTargetEnv string
+	copyStream    bool
+)
+
+func init() {
+	copyDBCmd.Flags().StringVar(&copyTargetEnv, "target-env", "", "config file section of the server to copy to, e.g. staging")
+	copyDBCmd.Flags().BoolVar(&copyStream, "stream", false, "copy by streaming rows with COPY instead of using a template")
+}
+
+// streamCopyDB copies a database by creating the target database, migrating
+// it, and streaming the rows of each table from the source database with
+// COPY. The target server is configured by the --target-env section of the
+// config file, or is the development server.
+func streamCopyDB(cmd *cobra.Command, source, target string) error {
+	var src, dst dbServer
+	src.initFromConfig()
+	src.dbName = source
+	env := copyTargetEnv
+	if env == "" {
+		env = "development"
+	}
+	dst.initFromConfigEnv(env)
+	if dst.host == "" {
+		return fmt.Errorf("no %q section in the config file", env)
+	}
+	dst.dbName = target
+	if src.host == dst.host && src.port == dst.port && source == target {
+		return errors.New("the source and target databases are the same")
+	}
+
+	// Timestamp command start.
+	start := time.Now()
+
+	// Create and migrate the target database. The schema_seeds table is
+	// created too, so that the record of executed seeds is copied with the
+	// rows they inserted.
+	ctx := context.Background()
+	err := createTargetDB(ctx, dst, env)
+	if err != nil {
+		return err
+	}
+	pool, err := connectMigrationPool(ctx, dst)
+	if err != nil {
+		return err
+	}
+	err = upMigrateSchema(ctx, pool, env)
+	if err == nil {
+		err = createSchemaSeedsTable(ctx, pool)
+	}
+	pool.Close()
+	if err != nil {
+		return err
+	}
+
+	// Connect to the source and target databases.
+	srcConn, err := pgx.Connect(ctx, src.dsn())
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close(ctx)
+	dstConn, err := pgx.Connect(ctx, dst.dsn())
+	if err != nil {
+		return err
+	}
+	defer dstConn.Close(ctx)
+
+	// Copy the rows in a single transaction, so that a failed copy leaves
+	// the target database empty.
+	err = copyRows(ctx, srcConn, dstConn)
+	if err != nil {
+		return err
+	}
+
+	// Timestamp command end.
+	duration := time.Since(start)
+
+	emit(event{
+		Event:      "database_copied",
+		Message:    fmt.Sprintf("Database %q copied to %q on %s. Command completed in %s.", source, target, env, duration),
+		Database:   target,
+		DurationMS: durationMS(duration),
+	})
+
+	return nil
+}
+
+// createTargetDB creates the target database of a streamed copy with the
+// options configured for its environment. It fails if the database already
+// exists, so that a copy never overwrites rows.
+func createTargetDB(ctx context.Context, dst dbServer, env string) error {
+	database := sqlt.Database{}
+	database.SetName(dst.dbName)
+	database.SetOwner(dst.user)
+	err := setCreateOptions(&database, env)
+	if err != nil {
+		return err
+	}
+	query, err := sqlt.ProcessTmpl(&database, sqlt.CreateDBTmpl)
+	if err != nil {
+		return err
+	}
+
+	dst.dbName = "" // dbName should be blank before getting DSN.
+	conn, err := pgx.Connect(ctx, dst.dsn())
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	exists, err := dbExists(ctx, conn, database.Name())
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("target database %q already exists", database.Name())
+	}
+
+	_, err = conn.Exec(ctx, query)
+
+	return err
+}
+
+// copyTablesSQL lists the ordinary tables of a database, including
+// partitions but not partitioned tables, except for system tables, tables
+// that belong to extensions and the schema_versions table, which is written
+// by the migrations.
+const copyTablesSQL = `SELECT format('%I.%I', n.nspname, c.relname)
+FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind = 'r'
+	AND n.nspname <> 'information_schema' AND n.nspname NOT LIKE 'pg\_%'
+	AND c.oid IS DISTINCT FROM to_regclass($1)
+	AND NOT EXISTS (
+		SELECT 1 FROM pg_depend d
+		WHERE d.classid = 'pg_class'::regclass AND d.objid = c.oid AND d.deptype = 'e'
+	)
+ORDER BY 1;`
+
+// copySequencesSQL lists the sequences of a database that have been used,
+// with their last values.
+const copySequencesSQL = `SELECT format('%I.%I', schemaname, sequencename), last_value
+FROM pg_sequences
+WHERE last_value IS NOT NULL
+ORDER BY 1;`
+
+// copyRows copies the rows of each table, and the value of each sequence,
+// from the source to the target database in a single transaction. Foreign
+// keys and triggers are disabled for the transaction, so tables can be copied
+// in any order.
+func copyRows(ctx context.Context, src, dst *pgx.Conn) error {
+	// List the tables to copy.
+	tables := make([]string, 0)
+	rows, err := src.Query(ctx, copyTablesSQL, schemaVersionsTable().Sanitize())
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var t string
+		err = rows.Scan(&t)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		tables = append(tables, t)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	// Begin a database transaction.
+	tx, err := dst.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "SET LOCAL session_replication_role = replica;")
+	if err != nil {
+		return fmt.Errorf("could not disable foreign keys and triggers: %w", err)
+	}
+
+	// Remove any rows inserted by the migrations.
+	if len(tables) > 0 {
+		_, err = tx.Exec(ctx, "TRUNCATE "+strings.Join(tables, ", ")+";")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Copy the rows of each table.
+	for i, t := range tables {
+		start := time.Now()
+		n, err := copyTable(ctx, src, dst, t)
+		if err != nil {
+			return fmt.Errorf("could not copy table %s: %w", t, err)
+		}
+		duration := time.Since(start)
+		emit(event{
+			Event:      "table_copied",
+			Message:    fmt.Sprintf("[%d/%d] Copied %d rows to table %s in %s.", i+1, len(tables), n, t, duration),
+			Table:      t,
+			Rows:       n,
+			DurationMS: durationMS(duration),
+		})
+	}
+
+	// Copy the value of each sequence.
+	rows, err = src.Query(ctx, copySequencesSQL)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var v int64
+		err = rows.Scan(&name, &v)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		values[name] = v
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+	for name, v := range values {
+		_, err = tx.Exec(ctx, "SELECT setval($1::regclass, $2);", name, v)
+		if err != nil {
+			return fmt.Errorf("could not set sequence %s: %w", name, err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// copyTable streams the rows of a table from the source to the target
+// database and returns the number of rows copied.
+func copyTable(ctx context.Context, src, dst *pgx.Conn, table string) (int64, error) {
+	r, w := io.Pipe()
+
+	// Write the rows of the source table to the pipe.
+	errc := make(chan error, 1)
+	go func() {
+		_, err := src.PgConn().CopyTo(ctx, w, "COPY "+table+" TO STDOUT;")
+		w.CloseWithError(err)
+		errc <- err
+	}()
+
+	// Read the rows into the target table.
+	tag, err := dst.PgConn().CopyFrom(ctx, r, "COPY "+table+" FROM STDIN;")
+	r.CloseWithError(err)
+	srcErr := <-errc
+	if srcErr != nil {
+		return 0, srcErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Unit test dbServer.initFromConfigEnv()
+func TestDBServerInitFromConfigEnv(t *testing.T) {
+	viper.Set("staging.host", "db.staging.example.com")
+	viper.Set("staging.port", 6432)
+	viper.Set("staging.database", "app_staging")
+	defer func() {
+		viper.Set("staging.host", nil)
+		viper.Set("staging.port", nil)
+		viper.Set("staging.database", nil)
+	}()
+
+	var srv dbServer
+	srv.initFromConfigEnv("staging")
+	if srv.host != "db.staging.example.com" || srv.port != 6432 || srv.dbName != "app_staging" {
+		t.Errorf("unexpected server config: %+v", srv)
+	}
+}
+
+// Unit test copyDB() argument validation
+func TestCopyRequiresTwoArguments(t *testing.T) {
+	err := copyDB(&cobra.Command{}, []string{"monarch_development"})
+	if err == nil {
+		t.Error("want error for one argument; got nil")
+	}
+}
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v4"
@@ -16,12 +18,44 @@ import (
 func init() {
 	rootCmd.AddCommand(dbCmd)
 	dbCmd.AddCommand(createDBCmd)
+	dbCmd.AddCommand(copyDBCmd)
 	dbCmd.AddCommand(dropDBCmd)
 	dbCmd.AddCommand(pingDBCmd)
 	dbCmd.AddCommand(renameDBCmd)
 	dbCmd.AddCommand(resetDBCmd)
+
+	for _, c := range []*cobra.Command{copyDBCmd, dropDBCmd, renameDBCmd, resetDBCmd} {
+		c.Flags().BoolVar(&dbForce, "force", false, "do not ask for confirmation")
+	}
+	for _, c := range []*cobra.Command{copyDBCmd, dropDBCmd, resetDBCmd} {
+		c.Flags().BoolVar(&dbTerminate, "terminate", false, "terminate other connections to the database")
+	}
+	for _, c := range []*cobra.Command{createDBCmd, resetDBCmd} {
+		c.Flags().StringVar(&dbEncoding, "encoding", "", "character set encoding, e.g. UTF8")
+		c.Flags().StringVar(&dbLCCollate, "lc-collate", "", "collation order, e.g. en_US.UTF-8")
+		c.Flags().StringVar(&dbLCCtype, "lc-ctype", "", "character classification, e.g. en_US.UTF-8")
+		c.Flags().StringVar(&dbTemplate, "template", "", "template database (default is template0 if the encoding or a locale is set)")
+		c.Flags().StringVar(&dbTablespace, "tablespace", "", "default tablespace")
+		c.Flags().IntVar(&dbConnectionLimit, "connection-limit", -1, "number of concurrent connections allowed (default is no limit)")
+		c.Flags().StringVar(&dbLocaleProvider, "locale-provider", "", "locale provider: libc or icu (PostgreSQL 15)")
+		c.Flags().StringVar(&dbICULocale, "icu-locale", "", "ICU locale, e.g. und-u-ks-level2, with --locale-provider icu")
+	}
 }
 
+// Database options set by command flags.
+var (
+	dbForce           bool
+	dbTerminate       bool
+	dbEncoding        string
+	dbLCCollate       string
+	dbLCCtype         string
+	dbTemplate        string
+	dbTablespace      string
+	dbConnectionLimit = -1
+	dbLocaleProvider  string
+	dbICULocale       string
+)
+
 // dbCmd ...
 var dbCmd = &cobra.Command{
 	Use:   "db",
@@ -32,7 +66,11 @@ var dbCmd = &cobra.Command{
 var createDBCmd = &cobra.Command{
 	Use:   "create",
 	Short: `Create a database with the name specificed by the "database" attribute in the config file.`,
-	RunE:  createDB,
+	Long: `Create a database with the name specificed by the "database" attribute in the config file.
+	The encoding, locale, template, tablespace and connection limit of the database are set by
+	flags, or by the "encoding", "lc_collate", "lc_ctype", "template", "tablespace",
+	"connection_limit", "locale_provider" and "icu_locale" attributes in the config file.`,
+	RunE: createDB,
 }
 
 // copyCmd ...
@@ -40,7 +78,17 @@ var copyDBCmd = &cobra.Command{
 	Use:   "copy [name] [targetname]",
 	Short: `Copy a database from name to targetname.`,
 	Long: `Copy a database from name to targetname. Note: Depending on the size of the source database,
-	it may take a while to complete copying.`,
+	it may take a while to complete copying.
+
+	The copy is made with CREATE DATABASE ... TEMPLATE, which fails while any other session is
+	connected to the source database. With --terminate, those sessions are terminated after
+	confirmation, or without it if --force is also given.
+
+	With --target-env, the target database is created on the server configured by that section
+	of the config file, e.g. "staging", instead. Its schema is created by the migrations, and
+	then the rows of each table are streamed from the source with COPY. Loading the rows
+	requires a superuser on the target server, because foreign keys and triggers are disabled
+	while the rows are copied. --stream copies the same way within one server.`,
 	RunE: copyDB,
 }
 
@@ -48,7 +96,11 @@ var copyDBCmd = &cobra.Command{
 var dropDBCmd = &cobra.Command{
 	Use:   "drop",
 	Short: `Drop a database with the name specificed by the "database" attribute in the config file.`,
-	RunE:  dropDB,
+	Long: `Drop a database with the name specificed by the "database" attribute in the config file.
+	The database name must be typed to confirm, unless --force is given. A database with other
+	connections is not dropped unless --terminate is given to terminate them. A database whose
+	config section sets "protected: true" is never dropped.`,
+	RunE: dropDB,
 }
 
 // pingDBCmd ...
@@ -63,7 +115,9 @@ var renameDBCmd = &cobra.Command{
 	Use:   "rename [oldname] [newname]",
 	Short: `Rename a database from name to newname.`,
 	Long: `Rename a database from name to newname. Note: if you change the name of your database,
-	you should also change the database name specificed in your config file.`,
+	you should also change the database name specificed in your config file. A database whose
+	config section sets "protected: true" is never renamed. Asks for confirmation unless --force
+	is set.`,
 	RunE: renameDB,
 }
 
@@ -71,7 +125,10 @@ var renameDBCmd = &cobra.Command{
 var resetDBCmd = &cobra.Command{
 	Use:   "reset",
 	Short: `First drops and then creates a database with the name specificed by the "database" attribute in the config file.`,
-	RunE:  resetDB,
+	Long: `First drops and then creates a database with the name specificed by the "database" attribute
+	in the config file. The database is dropped as by "db drop". With --migrate, the database is
+	then migrated, and with --seed, it is also seeded as by "db setup".`,
+	RunE: resetDB,
 }
 
 // createDB creates a database with the name specificed by the "database"
@@ -85,11 +142,15 @@ func createDB(cmd *cobra.Command, args []string) error {
 	database := sqlt.Database{}
 	database.SetName(srv.dbName)
 	database.SetOwner(srv.user)
+	err := setCreateOptions(&database, "development")
+	if err != nil {
+		return err
+	}
 
 	// Process the SQL template.
 	query, err := sqlt.ProcessTmpl(&database, sqlt.CreateDBTmpl)
 	if err != nil {
-		log.Fatalf("ERROR: createDB: %s\n", err)
+		return err
 	}
 
 	// Connect to the database server.
@@ -97,7 +158,7 @@ func createDB(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	conn, err := pgx.Connect(ctx, srv.dsn())
 	if err != nil {
-		log.Fatalf("ERROR: createDB: %s\n", err)
+		return err
 	}
 	defer conn.Close(ctx)
 
@@ -109,12 +170,18 @@ func createDB(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Database %q created. Command completed in %s.\n", database.Name(), duration)
+	emit(event{
+		Event:      "database_created",
+		Message:    fmt.Sprintf("Database %q created. Command completed in %s.", database.Name(), duration),
+		Database:   database.Name(),
+		DurationMS: durationMS(duration),
+	})
 
 	return err
 }
 
-// copyDB copies a database from within the same server.
+// copyDB copies a database from within the same server, or to another server
+// if a target environment is given.
 func copyDB(cmd *cobra.Command, args []string) error {
 	// Caller should supply name of an existing db as the first argument,
 	// and a name for copy target db as the second argument.
@@ -122,6 +189,11 @@ func copyDB(cmd *cobra.Command, args []string) error {
 		return errors.New("requires two arguments: name and targetname")
 	}
 
+	// Copy the database by streaming its rows to another server.
+	if copyTargetEnv != "" || copyStream {
+		return streamCopyDB(cmd, args[0], args[1])
+	}
+
 	// Initialize a dbServer object.
 	var srv dbServer
 	srv.initFromConfig()
@@ -134,7 +206,7 @@ func copyDB(cmd *cobra.Command, args []string) error {
 	// Process the SQL template.
 	query, err := sqlt.ProcessTmpl(&database, sqlt.CopyDBTmpl)
 	if err != nil {
-		log.Fatalf("ERROR: renameDB: %s\n", err)
+		return err
 	}
 
 	// Connect to the database server.
@@ -142,10 +214,35 @@ func copyDB(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	conn, err := pgx.Connect(ctx, srv.dsn())
 	if err != nil {
-		log.Fatalf("ERROR: renameDB: %s\n", err)
+		return err
 	}
 	defer conn.Close(ctx)
 
+	// A database cannot be used as a template while other sessions are
+	// connected to it.
+	n, err := countConnections(ctx, conn, database.Name())
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		if !dbTerminate {
+			return fmt.Errorf("database %q has %d other connection(s); close them or use --terminate", database.Name(), n)
+		}
+		if !dbForce {
+			ok, err := confirm(cmd, fmt.Sprintf("Terminate %d connection(s) to database %q?", n, database.Name()))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errors.New("canceled")
+			}
+		}
+		err = terminateConnections(ctx, conn, database.Name(), n)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Execute query to copy database.
 	start := time.Now()
 	_, err = conn.Exec(ctx, query)
@@ -154,16 +251,24 @@ func copyDB(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Database %q copied to %q. Command completed in %s.\n", database.Name(), database.CopyTargetName(), duration)
+	emit(event{
+		Event:      "database_copied",
+		Message:    fmt.Sprintf("Database %q copied to %q. Command completed in %s.", database.Name(), database.CopyTargetName(), duration),
+		Database:   database.CopyTargetName(),
+		DurationMS: durationMS(duration),
+	})
 
 	return err
 }
 
 // dropDB drops a database with the name specificed by the "database" attribute
-// in the viper config.
+// in the viper config, after the name has been typed to confirm.
 func dropDB(cmd *cobra.Command, args []string) error {
 	var srv dbServer
 	srv.initFromConfig()
+	if srv.protected {
+		return fmt.Errorf("database %q is protected and cannot be dropped", srv.dbName)
+	}
 
 	// Configure a data object to apply to a SQL template.
 	database := sqlt.Database{}
@@ -172,7 +277,6 @@ func dropDB(cmd *cobra.Command, args []string) error {
 	// Process SQL template
 	query, err := sqlt.ProcessTmpl(&database, sqlt.DropDBTmpl)
 	if err != nil {
-		log.Printf("ERROR: dropDB: %s\n", err)
 		return err
 	}
 
@@ -181,10 +285,52 @@ func dropDB(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	conn, err := pgx.Connect(ctx, srv.dsn())
 	if err != nil {
-		log.Fatalf("ERROR: dropDB: %s\n", err)
+		return err
 	}
 	defer conn.Close(ctx)
 
+	// Report a database that does not exist, rather than silently doing
+	// nothing.
+	exists, err := dbExists(ctx, conn, database.Name())
+	if err != nil {
+		return err
+	}
+	if !exists {
+		emit(event{
+			Event:    "database_missing",
+			Message:  fmt.Sprintf("Database %q does not exist.", database.Name()),
+			Database: database.Name(),
+		})
+		return nil
+	}
+
+	// Confirm by typing the database name.
+	if !dbForce {
+		ok, err := confirmName(cmd, database.Name())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("canceled: the typed name does not match")
+		}
+	}
+
+	// Refuse to drop a database that is in use, unless asked to terminate
+	// its connections.
+	n, err := countConnections(ctx, conn, database.Name())
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		if !dbTerminate {
+			return fmt.Errorf("database %q has %d other connection(s); close them or use --terminate", database.Name(), n)
+		}
+		err = terminateConnections(ctx, conn, database.Name(), n)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Execute query to drop database.
 	start := time.Now()
 	_, err = conn.Exec(ctx, query)
@@ -193,7 +339,12 @@ func dropDB(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Database %q dropped. Command completed in %s.\n", database.Name(), duration)
+	emit(event{
+		Event:      "database_dropped",
+		Message:    fmt.Sprintf("Database %q dropped. Command completed in %s.", database.Name(), duration),
+		Database:   database.Name(),
+		DurationMS: durationMS(duration),
+	})
 
 	return err
 }
@@ -218,7 +369,12 @@ func pingDB(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Database connection OK. Command completed in %s.\n", duration)
+	emit(event{
+		Event:      "database_pinged",
+		Message:    fmt.Sprintf("Database connection OK. Command completed in %s.", duration),
+		Database:   srv.dbName,
+		DurationMS: durationMS(duration),
+	})
 
 	return err
 }
@@ -234,6 +390,9 @@ func renameDB(cmd *cobra.Command, args []string) error {
 	// Initialize a dbServer object.
 	var srv dbServer
 	srv.initFromConfig()
+	if srv.protected {
+		return fmt.Errorf("database %q is protected and cannot be renamed", srv.dbName)
+	}
 
 	// Configure a data object to apply to a SQL template.
 	database := sqlt.Database{}
@@ -243,7 +402,18 @@ func renameDB(cmd *cobra.Command, args []string) error {
 	// Process the SQL template.
 	query, err := sqlt.ProcessTmpl(&database, sqlt.RenameDBTmpl)
 	if err != nil {
-		log.Fatalf("ERROR: renameDB: %s\n", err)
+		return err
+	}
+
+	// Confirm the rename, which breaks the connections of its users.
+	if !dbForce {
+		ok, err := confirm(cmd, fmt.Sprintf("Rename database %q to %q?", database.Name(), database.NewName()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("canceled")
+		}
 	}
 
 	// Connect to the database server.
@@ -251,7 +421,7 @@ func renameDB(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	conn, err := pgx.Connect(ctx, srv.dsn())
 	if err != nil {
-		log.Fatalf("ERROR: renameDB: %s\n", err)
+		return err
 	}
 	defer conn.Close(ctx)
 
@@ -263,12 +433,18 @@ func renameDB(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Database %q renamed to %q. Command completed in %s.\n", database.Name(), database.NewName(), duration)
+	emit(event{
+		Event:      "database_renamed",
+		Message:    fmt.Sprintf("Database %q renamed to %q. Command completed in %s.", database.Name(), database.NewName(), duration),
+		Database:   database.NewName(),
+		DurationMS: durationMS(duration),
+	})
 
 	return err
 }
 
-// resetDB drops and creates a database, i.e. reset.
+// resetDB drops and creates a database, i.e. reset. If asked, it then
+// migrates the database, and seeds it.
 func resetDB(cmd *cobra.Command, args []string) error {
 	err := dropDB(cmd, args)
 	if err != nil {
@@ -276,18 +452,32 @@ func resetDB(cmd *cobra.Command, args []string) error {
 	}
 
 	err = createDB(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if resetMigrate || resetSeed {
+		err = migrateDB(cmd, args)
+		if err != nil {
+			return err
+		}
+	}
+	if resetSeed {
+		err = seedDB(cmd, args)
+	}
 
 	return err
 }
 
 // dbServer
 type dbServer struct {
-	host     string
-	port     int
-	user     string
-	password string
-	dbName   string
-	sslMode  string
+	host      string
+	port      int
+	user      string
+	password  string
+	dbName    string
+	sslMode   string
+	protected bool
 }
 
 // dsn returns a Data Source Name (dsn) string based on the dbServer attributes.
@@ -308,11 +498,142 @@ func (s *dbServer) dsn() string {
 
 // intiFromConfig initalizes a dbServer{} from the viper config.
 func (s *dbServer) initFromConfig() {
+	s.initFromConfigEnv("development")
+}
+
+// initFromConfigEnv initalizes a dbServer{} from the section of the viper
+// config for an environment, e.g. "staging".
+func (s *dbServer) initFromConfigEnv(env string) {
 	// Read in config.
-	s.host = viper.GetString("development.host")
-	s.port = viper.GetInt("development.port")
-	s.user = viper.GetString("development.user")
-	s.password = viper.GetString("development.password")
-	s.dbName = viper.GetString("development.database")
-	s.sslMode = viper.GetString("development.sslmode")
+	s.host = viper.GetString(env + ".host")
+	s.port = viper.GetInt(env + ".port")
+	s.user = viper.GetString(env + ".user")
+	s.password = viper.GetString(env + ".password")
+	s.dbName = viper.GetString(env + ".database")
+	s.sslMode = viper.GetString(env + ".sslmode")
+	s.protected = viper.GetBool(env + ".protected")
+}
+
+// setCreateOptions sets the options of a database to be created from command
+// flags. A flag that is not set falls back to its attribute in the section of
+// the config file for an environment, e.g. "development.encoding".
+func setCreateOptions(d *sqlt.Database, env string) error {
+	option := func(flag, key string) string {
+		if flag != "" {
+			return flag
+		}
+		return viper.GetString(env + "." + key)
+	}
+
+	d.SetEncoding(option(dbEncoding, "encoding"))
+	d.SetLCCollate(option(dbLCCollate, "lc_collate"))
+	d.SetLCCtype(option(dbLCCtype, "lc_ctype"))
+	d.SetTemplate(option(dbTemplate, "template"))
+	d.SetTablespace(option(dbTablespace, "tablespace"))
+	d.SetLocaleProvider(option(dbLocaleProvider, "locale_provider"))
+	d.SetICULocale(option(dbICULocale, "icu_locale"))
+	if dbConnectionLimit != -1 {
+		d.SetConnectionLimit(dbConnectionLimit)
+	} else if viper.IsSet(env + ".connection_limit") {
+		d.SetConnectionLimit(viper.GetInt(env + ".connection_limit"))
+	}
+
+	switch d.LocaleProvider() {
+	case "", "libc", "icu":
+	default:
+		return fmt.Errorf("invalid locale provider %q: want libc or icu", d.LocaleProvider())
+	}
+	if d.ICULocale() != "" && d.LocaleProvider() != "icu" {
+		return errors.New("an ICU locale requires the icu locale provider")
+	}
+
+	return nil
+}
+
+// dbExists reports whether a database exists on the server of conn.
+func dbExists(ctx context.Context, conn *pgx.Conn, dbName string) (bool, error) {
+	var exists bool
+	err := conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1);`, dbName).Scan(&exists)
+
+	return exists, err
+}
+
+// connectDevelopmentDB connects to the development database, without waiting
+// long for it, so that generators can read the catalog. If no database is
+// configured or it cannot be reached, connectDevelopmentDB returns nil.
+func connectDevelopmentDB(ctx context.Context) *pgx.Conn {
+	var srv dbServer
+	srv.initFromConfig()
+	if srv.dbName == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	conn, err := pgx.Connect(ctx, srv.dsn())
+	if err != nil {
+		return nil
+	}
+
+	return conn
+}
+
+// countConnections counts the sessions connected to a database, other than
+// the session of conn.
+func countConnections(ctx context.Context, conn *pgx.Conn, dbName string) (int, error) {
+	sql := `SELECT count(*) FROM pg_stat_activity WHERE datname = $1 AND pid <> pg_backend_pid();`
+
+	var n int
+	err := conn.QueryRow(ctx, sql, dbName).Scan(&n)
+
+	return n, err
+}
+
+// terminateConnections terminates the n sessions connected to a database,
+// other than the session of conn.
+func terminateConnections(ctx context.Context, conn *pgx.Conn, dbName string, n int) error {
+	sql := `SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE datname = $1 AND pid <> pg_backend_pid();`
+	_, err := conn.Exec(ctx, sql, dbName)
+	if err != nil {
+		return err
+	}
+
+	emit(event{
+		Event:    "connections_terminated",
+		Message:  fmt.Sprintf("Terminated %d connection(s) to database %q.", n, dbName),
+		Database: dbName,
+		Count:    n,
+	})
+
+	return nil
+}
+
+// confirm asks a yes or no question on the command's output and reads the
+// answer from its input. Only "y" or "yes" is a yes.
+func confirm(cmd *cobra.Command, question string) (bool, error) {
+	fmt.Fprintf(promptOutput(cmd), "%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// confirmName asks for the name of a database to be typed on the command's
+// input to confirm that it may be dropped, and reports whether it matches.
+func confirmName(cmd *cobra.Command, dbName string) (bool, error) {
+	fmt.Fprintf(promptOutput(cmd), "This will permanently drop database %q and all of its data.\nType the database name to confirm: ", dbName)
+
+	answer, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	return strings.TrimSpace(answer) == dbName, nil
 }
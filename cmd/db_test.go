@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/jackc/pgx/v4"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -16,6 +19,8 @@ func TestCreateDB(t *testing.T) {
 
 	// Initialize configuration from config file.
 	initConfig()
+	dbForce = true // Do not ask for confirmation.
+	defer func() { dbForce = false }()
 
 	// Drop DB
 	err := dropDB(cmd, args)
@@ -43,6 +48,8 @@ func TestCopyDB(t *testing.T) {
 
 	// Initialize configuration from config file.
 	initConfig()
+	dbForce = true // Do not ask for confirmation.
+	defer func() { dbForce = false }()
 
 	// Create a DB with the default name.
 	err := resetDB(cmd, args)
@@ -101,6 +108,8 @@ func TestDropDB(t *testing.T) {
 
 	// Initialize configuration from config file.
 	initConfig()
+	dbForce = true // Do not ask for confirmation.
+	defer func() { dbForce = false }()
 
 	// Run dropDB() and verify that no errors occur.
 	err := dropDB(cmd, args)
@@ -129,6 +138,8 @@ func TestPingDB(t *testing.T) {
 
 	// Initialize configuration from config file.
 	initConfig()
+	dbForce = true // Do not ask for confirmation.
+	defer func() { dbForce = false }()
 
 	// Reset DB
 	err := resetDB(cmd, args)
@@ -156,6 +167,8 @@ func TestRenameDB(t *testing.T) {
 
 	// Initialize configuration from config file.
 	initConfig()
+	dbForce = true // Do not ask for confirmation.
+	defer func() { dbForce = false }()
 
 	// Create a DB with the default name.
 	err := resetDB(cmd, args)
@@ -196,6 +209,8 @@ func TestResetDB(t *testing.T) {
 
 	// Initialize configuration from config file.
 	initConfig()
+	dbForce = true // Do not ask for confirmation.
+	defer func() { dbForce = false }()
 
 	// Run ResetDB().
 	err := resetDB(cmd, args)
@@ -282,3 +297,127 @@ func TestDBServerInitFromConfig(t *testing.T) {
 		t.Errorf("want %q; got %q", expPort, actPort)
 	}
 }
+
+// Unit test confirm()
+func TestConfirm(t *testing.T) {
+	cases := []struct {
+		input string
+		exp   bool
+	}{
+		{"y\n", true},
+		{" YES \n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		cmd := &cobra.Command{}
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetIn(strings.NewReader(c.input))
+
+		act, err := confirm(cmd, "Continue?")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act != c.exp {
+			t.Errorf("%q: want %t; got %t", c.input, c.exp, act)
+		}
+		if exp := "Continue? [y/N] "; out.String() != exp {
+			t.Errorf("want %q; got %q", exp, out.String())
+		}
+	}
+}
+
+// Unit test renameDB() when the rename is not confirmed
+func TestCancelRenameDB(t *testing.T) {
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetIn(strings.NewReader("n\n"))
+
+	err := renameDB(cmd, []string{"monarch_development", "renamed_test_db"})
+	if err == nil || err.Error() != "canceled" {
+		t.Errorf("want canceled; got %v", err)
+	}
+	if exp := `Rename database "monarch_development" to "renamed_test_db"? [y/N] `; out.String() != exp {
+		t.Errorf("want %q; got %q", exp, out.String())
+	}
+}
+
+// Unit test confirmName()
+func TestConfirmName(t *testing.T) {
+	cases := []struct {
+		input string
+		exp   bool
+	}{
+		{"monarch_development\n", true},
+		{"monarch_development", true},
+		{"MONARCH_DEVELOPMENT\n", false},
+		{"y\n", false},
+	}
+	for _, c := range cases {
+		cmd := &cobra.Command{}
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetIn(strings.NewReader(c.input))
+
+		act, err := confirmName(cmd, "monarch_development")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act != c.exp {
+			t.Errorf("%q: want %t; got %t", c.input, c.exp, act)
+		}
+	}
+}
+
+// Unit test that a protected database is neither dropped nor renamed.
+func TestProtectedDatabase(t *testing.T) {
+	viper.Set("development.database", "monarch_production")
+	viper.Set("development.protected", true)
+	defer viper.Set("development.database", nil)
+	defer viper.Set("development.protected", nil)
+
+	cmd := &cobra.Command{}
+	for _, err := range []error{
+		dropDB(cmd, nil),
+		resetDB(cmd, nil),
+		renameDB(cmd, []string{"monarch_production", "monarch_old"}),
+	} {
+		if err == nil || !strings.Contains(err.Error(), "protected") {
+			t.Errorf("want protected error; got %v", err)
+		}
+	}
+}
+
+// Unit test setCreateOptions()
+func TestSetCreateOptions(t *testing.T) {
+	viper.Set("development.encoding", "LATIN1")
+	viper.Set("development.lc_collate", "C")
+	viper.Set("development.connection_limit", 10)
+	defer viper.Set("development.encoding", nil)
+	defer viper.Set("development.lc_collate", nil)
+	defer viper.Set("development.connection_limit", nil)
+
+	// A flag overrides the config file.
+	dbEncoding = "UTF8"
+	defer func() { dbEncoding = "" }()
+
+	d := sqlt.Database{}
+	err := setCreateOptions(&d, "development")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "TEMPLATE template0 ENCODING 'UTF8' LC_COLLATE 'C' CONNECTION LIMIT 10"
+	if act := d.Options(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	// An ICU locale requires the icu locale provider.
+	dbICULocale = "und"
+	defer func() { dbICULocale = "" }()
+	err = setCreateOptions(&d, "development")
+	if err == nil {
+		t.Error("want error for an ICU locale without the icu provider; got nil")
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/spf13/cobra"
+)
+
+// createExtensionRegexp matches a CREATE EXTENSION statement and captures the
+// extension name.
+var createExtensionRegexp = regexp.MustCompile(`(?i)\bCREATE\s+EXTENSION\s+(?:IF\s+NOT\s+EXISTS\s+)?("[^"]+"|[\w-]+)`)
+
+func init() {
+	dbCmd.AddCommand(doctorDBCmd)
+}
+
+// doctorDBCmd ...
+var doctorDBCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: `Check that the database server provides what the migrations need.`,
+	Long: `Check that the database server provides what the migrations need. The doctor reports
+	each extension that a migration creates, and whether the server provides it according to
+	pg_available_extensions. The command fails if any extension is missing.`,
+	RunE: doctorDB,
+}
+
+// doctorDB reports the extensions needed by the migrations that the database
+// server does not provide.
+func doctorDB(cmd *cobra.Command, args []string) error {
+	ms, err := readAllMigrations()
+	if err != nil {
+		return err
+	}
+	needed := requiredExtensions(ms)
+	if len(needed) == 0 {
+		emit(event{
+			Event:   "extensions_checked",
+			Message: "No extensions are needed by the migrations.",
+		})
+		return nil
+	}
+
+	var srv dbServer
+	srv.initFromConfig()
+
+	// Connect to the database server.
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, srv.dsn())
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	// Fetch the versions of the needed extensions that the server provides.
+	sql := `SELECT name, default_version, coalesce(installed_version, '')
+		FROM pg_available_extensions WHERE name = ANY ($1);`
+	rows, err := conn.Query(ctx, sql, needed)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	available := make(map[string]string)
+	for rows.Next() {
+		var name, defaultVersion, installedVersion string
+		err = rows.Scan(&name, &defaultVersion, &installedVersion)
+		if err != nil {
+			return err
+		}
+		if installedVersion != "" {
+			available[name] = fmt.Sprintf("installed version %s", installedVersion)
+		} else {
+			available[name] = fmt.Sprintf("available version %s", defaultVersion)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	// Report each needed extension.
+	missing := make([]string, 0)
+	for _, name := range needed {
+		if status, ok := available[name]; ok {
+			emit(event{
+				Event:     "extension_checked",
+				Message:   fmt.Sprintf("Extension %q: OK (%s)", name, status),
+				Extension: name,
+				Status:    "ok",
+			})
+			continue
+		}
+		emit(event{
+			Event:     "extension_checked",
+			Message:   fmt.Sprintf("Extension %q: MISSING (not provided by the server)", name),
+			Extension: name,
+			Status:    "missing",
+		})
+		missing = append(missing, name)
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("the server does not provide extensions needed by the migrations: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// requiredExtensions returns the sorted names of the extensions created by
+// the "up" SQL of the migrations.
+func requiredExtensions(ms []migration.Migration) []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, m := range ms {
+		for _, match := range createExtensionRegexp.FindAllStringSubmatch(m.UpSQL(), -1) {
+			name := match[1]
+			if strings.HasPrefix(name, `"`) {
+				name = strings.ReplaceAll(strings.Trim(name, `"`), `""`, `"`)
+			} else {
+				name = strings.ToLower(name)
+			}
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// readAllMigrations reads in all migrations in the migrations directory.
+func readAllMigrations() ([]migration.Migration, error) {
+	files, err := ioutil.ReadDir(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	ms := make([]migration.Migration, 0, len(files))
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), ".sql") {
+			continue
+		}
+
+		m := new(migration.Migration)
+		err = m.ReadFromFile(migrationsDir + "/" + f.Name())
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, *m)
+	}
+
+	return ms, nil
+}
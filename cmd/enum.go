@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+)
+
+// Enum value options set by command flags.
+var (
+	enumBefore string
+	enumAfter  string
+)
+
+func init() {
+	createCmd.AddCommand(createEnumCmd)
+	dropCmd.AddCommand(dropEnumCmd)
+	addCmd.AddCommand(addEnumValueCmd)
+	renameCmd.AddCommand(renameEnumValueCmd)
+
+	addEnumValueCmd.Flags().StringVar(&enumBefore, "before", "", "sort the new value before an existing value")
+	addEnumValueCmd.Flags().StringVar(&enumAfter, "after", "", "sort the new value after an existing value")
+}
+
+// createEnumCmd generates a migration file to create an enum type.
+var createEnumCmd = &cobra.Command{
+	Use:   "enum [name] [ [value] ... ]",
+	Short: "Generate a migration file to create an enum type named [name].",
+	Long: `Generate a migration file to create an enum type named [name] with the given values,
+	in sort order. Values are case sensitive and are used as given. A column can use the
+	type with the "enum(name)" type in a colName:type argument, for example:
+
+	  monarch g m create enum OrderStatus pending shipped delivered
+	  monarch g m create table orders status:"enum(OrderStatus)"`,
+	RunE: createEnumMigration,
+}
+
+// dropEnumCmd generates a migration file to drop an enum type.
+var dropEnumCmd = &cobra.Command{
+	Use:   "enum [name] [ [value] ... ]",
+	Short: "Generate a migration file to drop an enum type named [name].",
+	Long: `Generate a migration file to drop an enum type named [name]. If the values of the type
+	are given, the "down" migration will restore the type.`,
+	RunE: dropEnumMigration,
+}
+
+// addEnumValueCmd generates a migration file to add a value to an enum type.
+var addEnumValueCmd = &cobra.Command{
+	Use:   "enum-value [name] [value]",
+	Short: "Generate a migration file to add a value to an enum type named [name].",
+	Long: `Generate a migration file to add a value to an enum type named [name]. The value is
+	sorted last unless --before or --after is given.
+
+	The migration is executed outside of a transaction, since PostgreSQL before version 12
+	cannot add an enum value inside a transaction block. PostgreSQL cannot drop a value
+	from an enum type, so the migration has no "down" migration.`,
+	RunE: addEnumValueMigration,
+}
+
+// renameEnumValueCmd generates a migration file to rename a value of an enum type.
+var renameEnumValueCmd = &cobra.Command{
+	Use:   "enum-value [name] [value] [newValue]",
+	Short: "Generate a migration file to rename a value of an enum type from [value] to [newValue].",
+	RunE:  renameEnumValueMigration,
+}
+
+// createEnumMigration creates a migration file to create an enum type.
+func createEnumMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a type name as the first argument and one or more
+	// values as the following arguments.
+	if len(args) < 2 {
+		return errors.New("requires a name argument followed by one or more value arguments")
+	}
+
+	// Set enum data.
+	e := enumFromArgs(args)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(e, sqlt.CreateEnumTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(e, sqlt.DropEnumTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("CreateEnum_"+e.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// dropEnumMigration creates a migration file to drop an enum type.
+func dropEnumMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a type name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a name argument")
+	}
+
+	// Set enum data.
+	e := enumFromArgs(args)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(e, sqlt.DropEnumTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration, if the values are known.
+	var downSQL string
+	if len(e.Values()) > 0 {
+		downSQL, err = sqlt.ProcessTmpl(e, sqlt.CreateEnumTmpl)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Create migration file.
+	err = createMigration("DropEnum_"+e.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// addEnumValueMigration creates a migration file to add a value to an enum type.
+func addEnumValueMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a type name as the first argument and a value as
+	// the second argument.
+	if len(args) != 2 {
+		return errors.New("requires two arguments: name and value")
+	}
+	if enumBefore != "" && enumAfter != "" {
+		return errors.New("--before and --after cannot be used together")
+	}
+
+	// Set enum data.
+	e := new(sqlt.Enum)
+	e.SetName(args[0])
+	e.SetValue(args[1])
+	e.SetBefore(enumBefore)
+	e.SetAfter(enumAfter)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(e, sqlt.AddEnumValueTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigrationTx("AddEnumValueTo_"+e.Name(), upSQL, "", migration.TransactionNone)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// renameEnumValueMigration creates a migration file to rename a value of an enum type.
+func renameEnumValueMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a type name as the first argument, an existing
+	// value as the second argument and a new value as the third argument.
+	if len(args) != 3 {
+		return errors.New("requires three arguments: name, value and newValue")
+	}
+
+	// Set enum data.
+	e := new(sqlt.Enum)
+	e.SetName(args[0])
+	e.SetValue(args[1])
+	e.SetNewValue(args[2])
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(e, sqlt.RenameEnumValueTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	e.SetValue(args[2])    // swap value and newValue
+	e.SetNewValue(args[1]) // swap value and newValue
+	downSQL, err := sqlt.ProcessTmpl(e, sqlt.RenameEnumValueTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("RenameEnumValueOf_"+e.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// enumFromArgs configures an enum type from a type name argument followed by
+// value arguments.
+func enumFromArgs(args []string) *sqlt.Enum {
+	e := new(sqlt.Enum)
+	e.SetName(args[0])
+	for _, v := range args[1:] {
+		e.AddValue(v)
+	}
+
+	return e
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/spf13/cobra"
+)
+
+// Unit test createEnumMigration() and dropEnumMigration()
+func TestCreateAndDropEnumMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+
+	args := []string{"OrderStatus", "pending", "shipped", "won't ship"}
+	err := createEnumMigration(cmd, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dropEnumMigration(cmd, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(2, t)
+
+	createSQL := `CREATE TYPE order_status AS ENUM ('pending', 'shipped', 'won''t ship');`
+	dropSQL := `DROP TYPE IF EXISTS order_status;`
+
+	cases := [][]string{
+		{createSQL, ms[0].UpSQL()},
+		{dropSQL, ms[0].DownSQL()},
+		{dropSQL, ms[1].UpSQL()},
+		{createSQL, ms[1].DownSQL()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+
+	// An enum needs at least one value.
+	err = createEnumMigration(cmd, []string{"OrderStatus"})
+	if err == nil {
+		t.Error("want error; got nil")
+	}
+}
+
+// Unit test addEnumValueMigration()
+func TestAddEnumValueMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer func() { enumBefore, enumAfter = "", "" }()
+
+	enumAfter = "shipped"
+	err := addEnumValueMigration(cmd, []string{"OrderStatus", "delivered"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := readMigrationsHelper(1, t)[0]
+
+	exp := `ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'delivered' AFTER 'shipped';`
+	if act := m.UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+
+	// The migration must be executed outside of a transaction.
+	if exp, act := migration.TransactionNone, m.Transaction(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	// --before and --after are mutually exclusive.
+	enumBefore = "pending"
+	err = addEnumValueMigration(cmd, []string{"OrderStatus", "returned"})
+	if err == nil {
+		t.Error("want error; got nil")
+	}
+}
+
+// Unit test renameEnumValueMigration()
+func TestRenameEnumValueMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+
+	err := renameEnumValueMigration(cmd, []string{"OrderStatus", "shipped", "dispatched"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := readMigrationsHelper(1, t)[0]
+
+	exp := `ALTER TYPE order_status RENAME VALUE 'shipped' TO 'dispatched';`
+	if act := m.UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+
+	exp = `ALTER TYPE order_status RENAME VALUE 'dispatched' TO 'shipped';`
+	if act := m.DownSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Extension options set by command flags.
+var (
+	extSchema  string
+	extVersion string
+)
+
+func init() {
+	createCmd.AddCommand(createExtensionCmd)
+	dropCmd.AddCommand(dropExtensionCmd)
+
+	addExtensionFlags(createExtensionCmd.Flags())
+	addExtensionFlags(dropExtensionCmd.Flags())
+}
+
+// addExtensionFlags defines the flags shared by the extension commands.
+func addExtensionFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&extSchema, "schema", "", "schema for the objects of the extension (default is the current schema)")
+	fs.StringVar(&extVersion, "version", "", "version of the extension (default is the default version)")
+}
+
+// createExtensionCmd generates a migration file to create an extension.
+var createExtensionCmd = &cobra.Command{
+	Use:   "extension [name]",
+	Short: "Generate a migration file to create an extension named [name].",
+	Long: `Generate a migration file to create an extension named [name], e.g. pgcrypto, citext,
+	pg_trgm, postgis or uuid-ossp. The extension must be available on the database server;
+	"monarch db doctor" reports the extensions that the migrations need and the server does
+	not provide.`,
+	RunE: createExtensionMigration,
+}
+
+// dropExtensionCmd generates a migration file to drop an extension.
+var dropExtensionCmd = &cobra.Command{
+	Use:   "extension [name]",
+	Short: "Generate a migration file to drop an extension named [name].",
+	Long: `Generate a migration file to drop an extension named [name]. The flags should match those
+	used to create the extension so that the "down" migration can restore it.`,
+	RunE: dropExtensionMigration,
+}
+
+// createExtensionMigration creates a migration file to create an extension.
+func createExtensionMigration(cmd *cobra.Command, args []string) error {
+	// Set extension data.
+	e, err := extensionFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(e, sqlt.CreateExtensionTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(e, sqlt.DropExtensionTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("CreateExtension_"+e.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// dropExtensionMigration creates a migration file to drop an extension.
+func dropExtensionMigration(cmd *cobra.Command, args []string) error {
+	// Set extension data.
+	e, err := extensionFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(e, sqlt.DropExtensionTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(e, sqlt.CreateExtensionTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("DropExtension_"+e.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// extensionFromArgs configures an extension from command arguments and flags.
+func extensionFromArgs(args []string) (*sqlt.Extension, error) {
+	// Caller should supply an extension name as the first argument.
+	if len(args) < 1 {
+		return nil, errors.New("requires a name argument")
+	}
+
+	e := new(sqlt.Extension)
+	e.SetName(args[0])
+	e.SetSchema(extSchema)
+	e.SetVersion(extVersion)
+
+	return e, nil
+}
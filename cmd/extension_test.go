@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// Unit test createExtensionMigration() and dropExtensionMigration()
+func TestCreateAndDropExtensionMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer func() { extSchema, extVersion = "", "" }()
+
+	extSchema = "extensions"
+	extVersion = "1.1"
+	err := createExtensionMigration(cmd, []string{"uuid-ossp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dropExtensionMigration(cmd, []string{"uuid-ossp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(2, t)
+
+	createSQL := `CREATE EXTENSION IF NOT EXISTS "uuid-ossp" SCHEMA extensions VERSION '1.1';`
+	dropSQL := `DROP EXTENSION IF EXISTS "uuid-ossp";`
+	cases := [][]string{
+		{createSQL, ms[0].UpSQL()},
+		{dropSQL, ms[0].DownSQL()},
+		{dropSQL, ms[1].UpSQL()},
+		{createSQL, ms[1].DownSQL()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+}
+
+// Unit test requiredExtensions()
+func TestRequiredExtensions(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+
+	for _, v := range []string{"pgcrypto", "uuid-ossp", "pg_trgm", "pgcrypto"} {
+		err := createExtensionMigration(cmd, []string{v})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := createTableMigration(cmd, []string{"users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms, err := readAllMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{"pg_trgm", "pgcrypto", "uuid-ossp"}
+	act := requiredExtensions(ms)
+	if !reflect.DeepEqual(exp, act) {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
@@ -2,14 +2,51 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/kevinsapp/monarch/pkg/migration"
 	"github.com/kevinsapp/monarch/pkg/sqlt"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Foreign key options set by command flags.
+var (
+	fkColumn           string
+	fkReferencedColumn string
+	fkColumnType       string
+	fkOnDelete         string
+	fkOnUpdate         string
+	fkDeferrable       bool
+	fkNotNull          bool
+	fkExistingColumn   bool
+	fkOnline           bool
+	fkIndex            bool
 )
 
 func init() {
 	addCmd.AddCommand(addForeignKeyCmd)
 	dropCmd.AddCommand(dropForeignKeyCmd)
+
+	addForeignKeyFlags(addForeignKeyCmd.Flags())
+	addForeignKeyFlags(dropForeignKeyCmd.Flags())
+
+	addForeignKeyCmd.Flags().BoolVar(&fkOnline, "online", false, "add the constraint as NOT VALID and validate it in a separate migration")
+	addForeignKeyCmd.Flags().BoolVar(&fkIndex, "index", false, "also create an index on the referencing column")
+}
+
+// addForeignKeyFlags defines the flags shared by the foreign key commands.
+func addForeignKeyFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&fkColumn, "column", "", "referencing column name (default is [parentTableName]_id)")
+	fs.StringVar(&fkReferencedColumn, "references", "", "referenced column name (default is id)")
+	fs.StringVar(&fkColumnType, "type", "", "referencing column type (default matches the parent's primary key, or bigint)")
+	fs.StringVar(&fkOnDelete, "on-delete", "", "ON DELETE action: cascade, restrict, no action, set null or set default")
+	fs.StringVar(&fkOnUpdate, "on-update", "", "ON UPDATE action: cascade, restrict, no action, set null or set default")
+	fs.BoolVar(&fkDeferrable, "deferrable", false, "make the constraint DEFERRABLE INITIALLY DEFERRED")
+	fs.BoolVar(&fkNotNull, "not-null", false, "add the referencing column as NOT NULL")
+	fs.BoolVar(&fkExistingColumn, "existing-column", false, "attach the constraint to an existing column instead of adding one")
 }
 
 // addForeignKeyCmd generates a migration file to add a foreign key column and constraint to a table.
@@ -17,7 +54,12 @@ var addForeignKeyCmd = &cobra.Command{
 	Aliases: []string{"fk"},
 	Use:     "foreignkey [childTableName] [parentTableName]",
 	Short:   "Generate a migration file to add a foreign key to a table.",
-	RunE:    addForeignKeyMigration,
+	Long: `Generate a migration file to add a foreign key to a table.
+	With --online, the constraint is added as NOT VALID so that existing rows are not
+	scanned while the table is locked, and a later migration validates the constraint
+	in its own transaction, which does not block writes to either table. With --index as
+	well, the index is built CONCURRENTLY in a migration executed outside of a transaction.`,
+	RunE: addForeignKeyMigration,
 }
 
 // dropForeignKeyCmd generates a migration file to drop a foreign key column and constraint from a table.
@@ -30,16 +72,13 @@ var dropForeignKeyCmd = &cobra.Command{
 
 // addForeignKeyMigration creates a migration file to add a foreign key to a table.
 func addForeignKeyMigration(cmd *cobra.Command, args []string) error {
-	// Caller should supply a referencing table name (child table) as the first argument and a
-	// referenced table name (parent table) as the second argument.
-	if len(args) < 2 {
-		return errors.New("requires childTableName and parentTableName arguments")
+	// Set foreign key data.
+	fk, err := foreignKeyFromArgs(args)
+	if err != nil {
+		return err
 	}
 
-	// Set foreign key data.
-	fk := new(sqlt.ForeignKey)
-	fk.SetReferencingTableName(args[0])
-	fk.SetReferencedTableName(args[1])
+	fk.SetNotValid(fkOnline)
 
 	// Process SQL template for "up" migration.
 	upSQL, err := sqlt.ProcessTmpl(fk, sqlt.AddForeignKeyTmpl)
@@ -53,27 +92,73 @@ func addForeignKeyMigration(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Index the referencing column.
+	var createIdxSQL, dropIdxSQL string
+	if fkIndex {
+		idx := new(sqlt.Index)
+		idx.SetTableName(fk.ReferencingTableName())
+		idx.SetColumnName(fk.ReferencingColumnName())
+		idx.SetConcurrently(fkOnline)
+
+		createIdxSQL, err = sqlt.ProcessTmpl(idx, sqlt.CreateIndexTmpl)
+		if err != nil {
+			return err
+		}
+		dropIdxSQL, err = sqlt.ProcessTmpl(idx, sqlt.DropIndexTmpl)
+		if err != nil {
+			return err
+		}
+
+		// Offline: create the index after the constraint and drop it before the
+		// constraint, in the same migration.
+		if !fkOnline {
+			upSQL = upSQL + "\n\n" + createIdxSQL
+			downSQL = dropIdxSQL + "\n\n" + downSQL
+		}
+	}
+
 	// Create migration file.
 	err = createMigration("AddForeignKeyTo_"+fk.ReferencingTableName(), upSQL, downSQL)
 	if err != nil {
 		return err
 	}
 
+	// Online: build the index concurrently in a migration of its own, which is
+	// executed outside of a transaction.
+	if fkIndex && fkOnline {
+		name := "CreateIndexOn_" + fk.ReferencingTableName() + "_" + fk.ReferencingColumnName()
+		err = createMigrationTx(name, createIdxSQL, dropIdxSQL, migration.TransactionNone)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Online: create a migration to validate the constraint. It has no "down"
+	// migration, since the "down" migration of the first one drops the
+	// constraint.
+	if fkOnline {
+		validateSQL, err := sqlt.ProcessTmpl(fk, sqlt.ValidateForeignKeyTmpl)
+		if err != nil {
+			return err
+		}
+
+		name := "ValidateForeignKeyOn_" + fk.ReferencingTableName()
+		err = createMigrationTx(name, validateSQL, "", migration.TransactionIsolated)
+		if err != nil {
+			return err
+		}
+	}
+
 	return err
 }
 
 // dropForeignKeyMigration creates a migration file to add a foreign key to a table.
 func dropForeignKeyMigration(cmd *cobra.Command, args []string) error {
-	// Caller should supply a referencing table name (child table) as the first argument and a
-	// referenced table name (parent table) as the second argument.
-	if len(args) < 2 {
-		return errors.New("requires childTableName and parentTableName arguments")
-	}
-
 	// Set foreign key data.
-	fk := new(sqlt.ForeignKey)
-	fk.SetReferencingTableName(args[0])
-	fk.SetReferencedTableName(args[1])
+	fk, err := foreignKeyFromArgs(args)
+	if err != nil {
+		return err
+	}
 
 	// Process SQL template for "up" migration.
 	upSQL, err := sqlt.ProcessTmpl(fk, sqlt.DropForeignKeyTmpl)
@@ -95,3 +180,90 @@ func dropForeignKeyMigration(cmd *cobra.Command, args []string) error {
 
 	return err
 }
+
+// foreignKeyFromArgs configures a foreign key from command arguments and flags.
+func foreignKeyFromArgs(args []string) (*sqlt.ForeignKey, error) {
+	// Caller should supply a referencing table name (child table) as the first argument and a
+	// referenced table name (parent table) as the second argument.
+	if len(args) < 2 {
+		return nil, errors.New("requires childTableName and parentTableName arguments")
+	}
+
+	fk := new(sqlt.ForeignKey)
+	fk.SetReferencingTableName(args[0])
+	fk.SetReferencedTableName(args[1])
+
+	if fkColumn != "" {
+		fk.SetReferencingColumnName(fkColumn)
+	}
+	if fkReferencedColumn != "" {
+		fk.SetReferencedColumnName(fkReferencedColumn)
+	}
+	if fkColumnType != "" {
+		fk.SetReferencingColumnType(fkColumnType)
+	} else if fk.ReferencedColumnName() == "id" {
+		colType, err := parentKeyType(fk.ReferencedTableName())
+		if err != nil {
+			return nil, err
+		}
+		fk.SetReferencingColumnType(colType)
+	}
+	fk.SetDeferrable(fkDeferrable)
+	fk.SetNotNull(fkNotNull)
+	fk.SetExistingColumn(fkExistingColumn)
+
+	// Validate referential actions.
+	onDelete, err := referentialAction(fkOnDelete)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --on-delete: %s", err)
+	}
+	fk.SetOnDelete(onDelete)
+
+	onUpdate, err := referentialAction(fkOnUpdate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --on-update: %s", err)
+	}
+	fk.SetOnUpdate(onUpdate)
+
+	return fk, nil
+}
+
+// referentialAction normalizes a referential action such as "set-null" or
+// "SET_NULL" to its SQL form, "set null". An empty action is returned as is.
+func referentialAction(action string) (string, error) {
+	a := strings.ToLower(strings.TrimSpace(action))
+	a = strings.NewReplacer("-", " ", "_", " ").Replace(a)
+	a = strings.Join(strings.Fields(a), " ")
+
+	switch a {
+	case "", "cascade", "restrict", "no action", "set null", "set default":
+		return a, nil
+	}
+
+	return "", fmt.Errorf("unsupported referential action %q", action)
+}
+
+// parentKeyType returns the type of the `id` primary key column of a parent
+// table. The type is determined from the latest migration that created the
+// parent table; if there is none, it is determined from the
+// "generate.primary_key" config setting.
+func parentKeyType(parent string) (string, error) {
+	m, err := findLatestMigration("CreateTable_" + parent)
+	if err != nil {
+		return "", err
+	}
+
+	// Match the primary key column definition of each strategy.
+	if m != nil {
+		strategies := []string{sqlt.BigserialPrimaryKey, sqlt.IdentityPrimaryKey, sqlt.UUIDPrimaryKey}
+		for _, s := range strategies {
+			t := sqlt.Table{}
+			t.SetPrimaryKey(s)
+			if strings.Contains(m.UpSQL(), t.PrimaryKeyColumnDefinition()) {
+				return sqlt.PrimaryKeyType(s), nil
+			}
+		}
+	}
+
+	return sqlt.PrimaryKeyType(viper.GetString("generate.primary_key")), nil
+}
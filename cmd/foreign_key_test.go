@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/kevinsapp/monarch/pkg/migration"
@@ -24,6 +25,51 @@ ALTER TABLE cars
 	
 ALTER TABLE cars
 	DROP COLUMN IF EXISTS people_id;`
+
+	testAddForeignKeyOptionsSQL string = `ALTER TABLE cars
+	ADD COLUMN owner_uuid uuid NOT NULL;
+
+ALTER TABLE cars
+	ADD CONSTRAINT cars_owner_uuid_mnrk_fkc FOREIGN KEY (owner_uuid)
+	REFERENCES people (uuid)
+	ON DELETE SET NULL
+	ON UPDATE CASCADE
+	DEFERRABLE INITIALLY DEFERRED;`
+
+	testDropForeignKeyOptionsSQL string = `ALTER TABLE cars
+	DROP CONSTRAINT IF EXISTS cars_owner_uuid_mnrk_fkc;
+	
+ALTER TABLE cars
+	DROP COLUMN IF EXISTS owner_uuid;`
+
+	testAddForeignKeyExistingColumnSQL string = `ALTER TABLE cars
+	ADD CONSTRAINT cars_people_mnrk_fkc FOREIGN KEY (people_id)
+	REFERENCES people (id)
+	ON DELETE CASCADE;`
+
+	testDropForeignKeyExistingColumnSQL string = `ALTER TABLE cars
+	DROP CONSTRAINT IF EXISTS cars_people_mnrk_fkc;`
+
+	testAddForeignKeyOnlineSQL string = `ALTER TABLE cars
+	ADD COLUMN people_id bigint;
+
+ALTER TABLE cars
+	ADD CONSTRAINT cars_people_mnrk_fkc FOREIGN KEY (people_id)
+	REFERENCES people (id)
+	NOT VALID;`
+
+	testDropForeignKeyOnlineSQL string = `ALTER TABLE cars
+	DROP CONSTRAINT IF EXISTS cars_people_mnrk_fkc;
+	
+ALTER TABLE cars
+	DROP COLUMN IF EXISTS people_id;`
+
+	testCreateForeignKeyIndexOnlineSQL string = `CREATE INDEX CONCURRENTLY cars_people_id_mnrk_idx ON cars (people_id);`
+
+	testDropForeignKeyIndexOnlineSQL string = `DROP INDEX CONCURRENTLY IF EXISTS cars_people_id_mnrk_idx;`
+
+	testValidateForeignKeySQL string = `ALTER TABLE cars
+	VALIDATE CONSTRAINT cars_people_mnrk_fkc;`
 )
 
 // Unit test createTableMigrations()
@@ -125,3 +171,189 @@ func TestDropForeignKeyMigrations(t *testing.T) {
 		t.Errorf("\nwant %q;\ngot %q\n", exp, act)
 	}
 }
+
+// Unit test addForeignKeyMigration() with column, action and deferral options.
+func TestAddForeignKeyMigrationOptions(t *testing.T) {
+	cases := []struct {
+		setFlags func()
+		upSQL    string
+		downSQL  string
+	}{
+		{
+			func() {
+				fkColumn = "ownerUUID"
+				fkReferencedColumn = "uuid"
+				fkColumnType = "uuid"
+				fkOnDelete = "set-null"
+				fkOnUpdate = "CASCADE"
+				fkDeferrable = true
+				fkNotNull = true
+			},
+			testAddForeignKeyOptionsSQL,
+			testDropForeignKeyOptionsSQL,
+		},
+		{
+			func() {
+				fkIndex = true
+			},
+			testAddForeignKeySQL + "\n\nCREATE INDEX cars_people_id_mnrk_idx ON cars (people_id);",
+			"DROP INDEX IF EXISTS cars_people_id_mnrk_idx;\n\n" + testDropForeignKeySQL,
+		},
+		{
+			func() {
+				fkOnDelete = "cascade"
+				fkExistingColumn = true
+			},
+			testAddForeignKeyExistingColumnSQL,
+			testDropForeignKeyExistingColumnSQL,
+		},
+	}
+
+	for _, c := range cases {
+		// Create a migrations directory.
+		cmd := &cobra.Command{}
+		mkdirMigrations(cmd, nil)
+
+		// Run addForeignKeyMigration() with flags set.
+		c.setFlags()
+		err := addForeignKeyMigration(cmd, []string{"cars", "people"})
+		resetForeignKeyFlagsHelper()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := readMigrationsHelper(1, t)[0]
+
+		// Verify that the upSQL is as expected
+		if exp, act := c.upSQL, m.UpSQL(); exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+
+		// Verify that the downSQL is as expected
+		if exp, act := c.downSQL, m.DownSQL(); exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+
+		os.RemoveAll(migrationsDir) // Do cleanup
+	}
+}
+
+// Unit test addForeignKeyMigration() with --online and --index.
+func TestAddForeignKeyMigrationOnline(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+
+	// Run addForeignKeyMigration() with flags set.
+	fkOnline = true
+	fkIndex = true
+	err := addForeignKeyMigration(cmd, []string{"cars", "people"})
+	resetForeignKeyFlagsHelper()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(3, t)
+
+	// Verify the NOT VALID migration.
+	if exp, act := testAddForeignKeyOnlineSQL, ms[0].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+	if exp, act := testDropForeignKeyOnlineSQL, ms[0].DownSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+	if exp, act := migration.TransactionDefault, ms[0].Transaction(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	// Verify the CREATE INDEX CONCURRENTLY migration runs outside a transaction.
+	if exp, act := testCreateForeignKeyIndexOnlineSQL, ms[1].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+	if exp, act := testDropForeignKeyIndexOnlineSQL, ms[1].DownSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+	if exp, act := migration.TransactionNone, ms[1].Transaction(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	// Verify the VALIDATE CONSTRAINT migration runs in its own transaction.
+	if exp, act := testValidateForeignKeySQL, ms[2].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+	if exp, act := migration.TransactionIsolated, ms[2].Transaction(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test addForeignKeyMigration() with a parent table that has a uuid key.
+func TestAddForeignKeyMigrationParentKeyType(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+
+	// Create the parent table with a uuid primary key.
+	tablePrimaryKey = "uuid"
+	err := createTableMigration(cmd, []string{"people"})
+	tablePrimaryKey = ""
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run addForeignKeyMigration()
+	err = addForeignKeyMigration(cmd, []string{"cars", "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(2, t)
+
+	// Verify that the referencing column type matches the parent's key.
+	exp := "ADD COLUMN people_id uuid;"
+	if act := ms[1].UpSQL(); !strings.Contains(act, exp) {
+		t.Errorf("\nwant %q in\n %q\n", exp, act)
+	}
+}
+
+// Unit test referentialAction()
+func TestReferentialAction(t *testing.T) {
+	cases := [][]string{
+		{"", ""},
+		{"cascade", "cascade"},
+		{"RESTRICT", "restrict"},
+		{"set-null", "set null"},
+		{"set_default", "set default"},
+		{"no  action", "no action"},
+	}
+	for _, c := range cases {
+		act, err := referentialAction(c[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := c[1]; exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+	}
+
+	// Verify that an unsupported action is an error.
+	_, err := referentialAction("delete")
+	if err == nil {
+		t.Error("want error; got nil")
+	}
+}
+
+// resetForeignKeyFlagsHelper restores the foreign key flags to their defaults.
+func resetForeignKeyFlagsHelper() {
+	fkColumn = ""
+	fkReferencedColumn = ""
+	fkColumnType = ""
+	fkOnDelete = ""
+	fkOnUpdate = ""
+	fkDeferrable = false
+	fkNotNull = false
+	fkExistingColumn = false
+	fkOnline = false
+	fkIndex = false
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Function options set by command flags.
+var (
+	fnReturns  string
+	fnLanguage string
+	fnBodyFile string
+)
+
+func init() {
+	createCmd.AddCommand(createFunctionCmd)
+	createCmd.AddCommand(createProcedureCmd)
+	dropCmd.AddCommand(dropFunctionCmd)
+	dropCmd.AddCommand(dropProcedureCmd)
+
+	addFunctionFlags(createFunctionCmd.Flags())
+	addFunctionFlags(createProcedureCmd.Flags())
+	createFunctionCmd.Flags().StringVar(&fnReturns, "returns", "void", "return type, e.g. integer, trigger or \"SETOF users\"")
+}
+
+// addFunctionFlags defines the flags shared by the create function and create
+// procedure commands.
+func addFunctionFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&fnLanguage, "language", "plpgsql", "implementation language")
+	fs.StringVar(&fnBodyFile, "body-file", "", "read the body from a file (default is a scaffold to fill in)")
+}
+
+// createFunctionCmd generates a migration file to create or replace a function.
+var createFunctionCmd = &cobra.Command{
+	Use:   "function [name] [ [argName:type[=default]] ... ]",
+	Short: "Generate a migration file to create or replace a function named [name].",
+	Long: `Generate a migration file to create or replace a function named [name]. The body is read
+	from --body-file, or scaffolded to be filled in. It is dollar-quoted with a tag that does
+	not occur in the body, so the body needs no escaping. For example:
+
+	  monarch g m create function addTax amount:numeric rate:numeric=0.2 --returns numeric
+	  monarch g m create function stampUser --returns trigger --body-file stamp_user.sql
+
+	If an earlier migration created the function with the same arguments, the "down" migration
+	restores that definition; otherwise it drops the function. Note that CREATE OR REPLACE
+	cannot change the return type of an existing function.`,
+	RunE: createFunctionMigration,
+}
+
+// createProcedureCmd generates a migration file to create or replace a procedure.
+var createProcedureCmd = &cobra.Command{
+	Use:   "procedure [name] [ [argName:type[=default]] ... ]",
+	Short: "Generate a migration file to create or replace a procedure named [name].",
+	Long: `Generate a migration file to create or replace a procedure named [name]. Procedures
+	require PostgreSQL 11. See "create function" for details.`,
+	RunE: createProcedureMigration,
+}
+
+// dropFunctionCmd generates a migration file to drop a function.
+var dropFunctionCmd = &cobra.Command{
+	Use:   "function [name] [ [argName:type] ... ]",
+	Short: "Generate a migration file to drop a function named [name].",
+	Long: `Generate a migration file to drop a function named [name]. The arguments should match
+	those used to create the function so that the "down" migration can restore it.`,
+	RunE: dropFunctionMigration,
+}
+
+// dropProcedureCmd generates a migration file to drop a procedure.
+var dropProcedureCmd = &cobra.Command{
+	Use:   "procedure [name] [ [argName:type] ... ]",
+	Short: "Generate a migration file to drop a procedure named [name].",
+	Long: `Generate a migration file to drop a procedure named [name]. The arguments should match
+	those used to create the procedure so that the "down" migration can restore it.`,
+	RunE: dropProcedureMigration,
+}
+
+// createFunctionMigration creates a migration file to create or replace a function.
+func createFunctionMigration(cmd *cobra.Command, args []string) error {
+	return createFunction(args, false)
+}
+
+// createProcedureMigration creates a migration file to create or replace a procedure.
+func createProcedureMigration(cmd *cobra.Command, args []string) error {
+	return createFunction(args, true)
+}
+
+// dropFunctionMigration creates a migration file to drop a function.
+func dropFunctionMigration(cmd *cobra.Command, args []string) error {
+	return dropFunction(args, false)
+}
+
+// dropProcedureMigration creates a migration file to drop a procedure.
+func dropProcedureMigration(cmd *cobra.Command, args []string) error {
+	return dropFunction(args, true)
+}
+
+// createFunction creates a migration file to create or replace a function or
+// a procedure.
+func createFunction(args []string, procedure bool) error {
+	// Set function data.
+	f, err := functionFromArgs(args, procedure)
+	if err != nil {
+		return err
+	}
+	f.SetLanguage(fnLanguage)
+	if !procedure {
+		f.SetReturns(fnReturns)
+	}
+	if fnBodyFile != "" {
+		body, err := fileutil.ReadFileAsString(fnBodyFile)
+		if err != nil {
+			return err
+		}
+		f.SetBody(body)
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(f, sqlt.CreateFunctionTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration. If the function replaces
+	// one with the same arguments, restore the previous definition.
+	prevSQL, err := previousFunctionSQL(f)
+	if err != nil {
+		return err
+	}
+	downSQL := prevSQL
+	if downSQL == "" {
+		downSQL, err = sqlt.ProcessTmpl(f, sqlt.DropFunctionTmpl)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Create migration file.
+	err = createMigration(functionMigrationPrefix("Create", f)+f.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// dropFunction creates a migration file to drop a function or a procedure.
+func dropFunction(args []string, procedure bool) error {
+	// Set function data.
+	f, err := functionFromArgs(args, procedure)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(f, sqlt.DropFunctionTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Restore the previous definition in the "down" migration, if it can be
+	// found.
+	downSQL, err := previousFunctionSQL(f)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration(functionMigrationPrefix("Drop", f)+f.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// functionFromArgs configures a function from a name argument followed by
+// argument specs of the form "argName:type[=default]".
+func functionFromArgs(args []string, procedure bool) (*sqlt.Function, error) {
+	// Caller should supply a function name as the first argument.
+	if len(args) < 1 {
+		return nil, errors.New("requires a name argument")
+	}
+
+	f := new(sqlt.Function)
+	f.SetName(args[0])
+	f.SetProcedure(procedure)
+	for _, v := range args[1:] {
+		nameType := strings.SplitN(v, ":", 2)
+		if len(nameType) < 2 || nameType[1] == "" {
+			return nil, fmt.Errorf("argument %q should have the form argName:type", v)
+		}
+
+		a := sqlt.FunctionArgument{}
+		a.SetName(nameType[0])
+		typeDefault := strings.SplitN(nameType[1], "=", 2)
+		a.SetType(typeDefault[0])
+		if len(typeDefault) > 1 {
+			a.SetDefault(typeDefault[1])
+		}
+		f.AddArgument(a)
+	}
+
+	return f, nil
+}
+
+// previousFunctionSQL returns the "up" SQL of the latest migration that
+// created a function with the same name and arguments as "f", unless the
+// function was dropped after it. If there is no such migration,
+// previousFunctionSQL returns an empty string.
+func previousFunctionSQL(f *sqlt.Function) (string, error) {
+	created, err := findLatestMigration(functionMigrationPrefix("Create", f) + f.Name())
+	if err != nil || created == nil {
+		return "", err
+	}
+	dropped, err := findLatestMigration(functionMigrationPrefix("Drop", f) + f.Name())
+	if err != nil {
+		return "", err
+	}
+	if dropped != nil && dropped.Version() > created.Version() {
+		return "", nil
+	}
+
+	// A function with different arguments is a separate overload.
+	header := fmt.Sprintf("CREATE OR REPLACE %s %s(%s)", f.Kind(), f.Name(), f.ArgumentList())
+	if !strings.HasPrefix(created.UpSQL(), header) {
+		return "", nil
+	}
+
+	return created.UpSQL(), nil
+}
+
+// functionMigrationPrefix returns the migration name prefix for an action on
+// a function or a procedure.
+func functionMigrationPrefix(action string, f *sqlt.Function) string {
+	if f.Procedure() {
+		return action + "Procedure_"
+	}
+	return action + "Function_"
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+	"github.com/spf13/cobra"
+)
+
+// Unit test createFunctionMigration() and dropFunctionMigration()
+func TestCreateAndDropFunctionMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetFunctionFlagsHelper()
+
+	// Create a function with the scaffolded body.
+	fnReturns = "numeric"
+	args := []string{"addTax", "amount:numeric", "rate:numeric=0.2"}
+	err := createFunctionMigration(cmd, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace it with a body that contains the default dollar-quote tag.
+	fn := migrationsDir + "/add_tax_body.sql"
+	err = fileutil.CreateAndWriteString(fn, "\nSELECT amount * (1 + rate) -- not $body$\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fnLanguage = "SQL"
+	fnBodyFile = fn
+	err = createFunctionMigration(cmd, args)
+	os.Remove(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Drop it.
+	err = dropFunctionMigration(cmd, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(3, t)
+
+	createSQL := `CREATE OR REPLACE FUNCTION add_tax(amount numeric, rate numeric DEFAULT 0.2)
+	RETURNS numeric
+	LANGUAGE plpgsql
+AS $body$
+BEGIN
+	-- Write the body here.
+END;
+$body$;`
+	replaceSQL := `CREATE OR REPLACE FUNCTION add_tax(amount numeric, rate numeric DEFAULT 0.2)
+	RETURNS numeric
+	LANGUAGE sql
+AS $body1$
+SELECT amount * (1 + rate) -- not $body$
+$body1$;`
+	dropSQL := `DROP FUNCTION IF EXISTS add_tax(numeric, numeric);`
+
+	cases := [][]string{
+		{createSQL, ms[0].UpSQL()},
+		{dropSQL, ms[0].DownSQL()},
+		{replaceSQL, ms[1].UpSQL()},
+		{createSQL, ms[1].DownSQL()},
+		{dropSQL, ms[2].UpSQL()},
+		{replaceSQL, ms[2].DownSQL()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+}
+
+// Unit test createProcedureMigration()
+func TestCreateProcedureMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetFunctionFlagsHelper()
+
+	err := createProcedureMigration(cmd, []string{"archiveOrders", "before:date"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := readMigrationsHelper(1, t)[0]
+
+	exp := `CREATE OR REPLACE PROCEDURE archive_orders(before date)
+	LANGUAGE plpgsql
+AS $body$
+BEGIN
+	-- Write the body here.
+END;
+$body$;`
+	if act := m.UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+
+	exp = `DROP PROCEDURE IF EXISTS archive_orders(date);`
+	if act := m.DownSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+
+	// Arguments must have a type.
+	err = createProcedureMigration(cmd, []string{"archiveOrders", "before"})
+	if err == nil {
+		t.Error("want error; got nil")
+	}
+}
+
+// resetFunctionFlagsHelper resets the function options set by command flags.
+func resetFunctionFlagsHelper() {
+	fnReturns = ""
+	fnLanguage = ""
+	fnBodyFile = ""
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Grant options set by command flags.
+var (
+	grantTo              []string
+	grantWithGrantOption bool
+	grantAllInSchema     bool
+	grantOn              string
+	grantForRole         string
+)
+
+func init() {
+	migrationCmd.AddCommand(grantCmd)
+	migrationCmd.AddCommand(revokeCmd)
+
+	for _, objectType := range []string{sqlt.TableObject, sqlt.SequenceObject, sqlt.SchemaObject} {
+		g := &cobra.Command{
+			Use:   objectType + " [name[,name...]] [privilege...]",
+			Short: fmt.Sprintf("Generate a migration file to grant privileges on a %s.", objectType),
+			Long:  grantLong(objectType, "grant"),
+			RunE:  grantMigration(objectType),
+		}
+		r := &cobra.Command{
+			Use:   objectType + " [name[,name...]] [privilege...]",
+			Short: fmt.Sprintf("Generate a migration file to revoke privileges on a %s.", objectType),
+			Long:  grantLong(objectType, "revoke"),
+			RunE:  revokeMigration(objectType),
+		}
+		addGrantFlags(g.Flags(), objectType != sqlt.SchemaObject)
+		addGrantFlags(r.Flags(), objectType != sqlt.SchemaObject)
+		grantCmd.AddCommand(g)
+		revokeCmd.AddCommand(r)
+	}
+
+	grantCmd.AddCommand(grantDefaultPrivilegesCmd)
+	revokeCmd.AddCommand(revokeDefaultPrivilegesCmd)
+	addDefaultPrivilegesFlags(grantDefaultPrivilegesCmd.Flags())
+	addDefaultPrivilegesFlags(revokeDefaultPrivilegesCmd.Flags())
+}
+
+// addGrantFlags defines the flags shared by the grant and revoke commands.
+func addGrantFlags(fs *pflag.FlagSet, allInSchema bool) {
+	fs.StringSliceVar(&grantTo, "to", nil, "comma-separated roles, or PUBLIC, that the privileges are granted to or revoked from")
+	fs.BoolVar(&grantWithGrantOption, "with-grant-option", false, "allow the roles to grant the privileges to other roles")
+	if allInSchema {
+		fs.BoolVar(&grantAllInSchema, "all-in-schema", false, "[name] is a schema, and the privileges are on all its objects of the type")
+	}
+}
+
+// addDefaultPrivilegesFlags defines the flags shared by the default-privileges
+// commands.
+func addDefaultPrivilegesFlags(fs *pflag.FlagSet) {
+	addGrantFlags(fs, false)
+	fs.StringVar(&grantOn, "on", "tables", "type of the objects: tables or sequences")
+	fs.StringVar(&grantForRole, "for-role", "", "role whose new objects the privileges apply to (default is the current user)")
+}
+
+// grantLong returns the long description of a grant or revoke command.
+func grantLong(objectType, verb string) string {
+	return fmt.Sprintf(`Generate a migration file to %s privileges on one or more %ss. Each privilege is one of
+	%s, or ALL. The "down" migration reverses the %s. For example:
+
+	  monarch g m %s %s [name] %s --to app_user`,
+		verb, objectType, strings.Join(sqlt.Privileges(objectType), ", "), verb,
+		verb, objectType, strings.ToLower(sqlt.Privileges(objectType)[0]))
+}
+
+// grantCmd ...
+var grantCmd = &cobra.Command{
+	Use: "grant",
+}
+
+// revokeCmd ...
+var revokeCmd = &cobra.Command{
+	Use: "revoke",
+}
+
+// grantDefaultPrivilegesCmd generates a migration file to alter default
+// privileges by granting privileges on objects created later.
+var grantDefaultPrivilegesCmd = &cobra.Command{
+	Use:   "default-privileges [schema[,schema...]] [privilege...]",
+	Short: "Generate a migration file to grant privileges on objects created later in a schema.",
+	Long: `Generate a migration file to grant privileges on the tables or sequences that are created
+	later in a schema, with ALTER DEFAULT PRIVILEGES. The privileges apply to objects created by
+	the --for-role role. For example:
+
+	  monarch g m grant default-privileges public select --to readers --for-role app_owner`,
+	RunE: grantMigration(""),
+}
+
+// revokeDefaultPrivilegesCmd generates a migration file to alter default
+// privileges by revoking privileges on objects created later.
+var revokeDefaultPrivilegesCmd = &cobra.Command{
+	Use:   "default-privileges [schema[,schema...]] [privilege...]",
+	Short: "Generate a migration file to revoke privileges on objects created later in a schema.",
+	RunE:  revokeMigration(""),
+}
+
+// grantMigration returns a function that creates a migration file to grant
+// privileges on objects of a type. An empty object type means default
+// privileges on the type set by --on.
+func grantMigration(objectType string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		g, err := grantFromArgs(objectType, args)
+		if err != nil {
+			return err
+		}
+
+		// Process SQL template for "up" migration.
+		upSQL, err := sqlt.ProcessTmpl(g, sqlt.GrantTmpl)
+		if err != nil {
+			return err
+		}
+
+		// Process SQL template for "down" migration.
+		downSQL, err := sqlt.ProcessTmpl(g, sqlt.RevokeTmpl)
+		if err != nil {
+			return err
+		}
+
+		// Create migration file.
+		err = createMigration("Grant"+grantMigrationName(g, args[0]), upSQL, downSQL)
+		if err != nil {
+			return err
+		}
+
+		return err
+	}
+}
+
+// revokeMigration returns a function that creates a migration file to revoke
+// privileges on objects of a type. An empty object type means default
+// privileges on the type set by --on.
+func revokeMigration(objectType string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		g, err := grantFromArgs(objectType, args)
+		if err != nil {
+			return err
+		}
+
+		// Process SQL template for "up" migration.
+		upSQL, err := sqlt.ProcessTmpl(g, sqlt.RevokeTmpl)
+		if err != nil {
+			return err
+		}
+
+		// Process SQL template for "down" migration.
+		downSQL, err := sqlt.ProcessTmpl(g, sqlt.GrantTmpl)
+		if err != nil {
+			return err
+		}
+
+		// Create migration file.
+		err = createMigration("Revoke"+grantMigrationName(g, args[0]), upSQL, downSQL)
+		if err != nil {
+			return err
+		}
+
+		return err
+	}
+}
+
+// grantMigrationName returns the part of a grant or revoke migration name
+// that follows the verb, e.g. `OnTable_users`.
+func grantMigrationName(g *sqlt.Grant, objects string) string {
+	objects = strings.ReplaceAll(objects, ",", "_")
+	switch {
+	case g.Defaults():
+		return "DefaultPrivilegesIn_" + objects
+	case g.AllInSchema():
+		return "OnAll" + strcase.ToCamel(g.ObjectType()) + "sIn_" + objects
+	}
+	return "On" + strcase.ToCamel(g.ObjectType()) + "_" + objects
+}
+
+// grantFromArgs configures a grant from command arguments and flags.
+func grantFromArgs(objectType string, args []string) (*sqlt.Grant, error) {
+	// Caller should supply object names and at least one privilege.
+	if len(args) < 2 {
+		return nil, errors.New("requires a name argument and at least one privilege")
+	}
+	if len(grantTo) == 0 {
+		return nil, errors.New("requires --to with at least one role")
+	}
+
+	g := new(sqlt.Grant)
+	if objectType == "" {
+		g.SetDefaults(true)
+		g.SetForRole(grantForRole)
+		objectType = strings.TrimSuffix(strings.ToLower(grantOn), "s")
+		if objectType != sqlt.TableObject && objectType != sqlt.SequenceObject {
+			return nil, fmt.Errorf("invalid --on %q: want tables or sequences", grantOn)
+		}
+	}
+	err := g.SetObjectType(objectType)
+	if err != nil {
+		return nil, err
+	}
+	g.SetAllInSchema(grantAllInSchema)
+	g.SetWithGrantOption(grantWithGrantOption)
+
+	for _, v := range strings.Split(args[0], ",") {
+		g.AddObject(v)
+	}
+	for _, v := range args[1:] {
+		for _, p := range strings.Split(v, ",") {
+			err = g.AddPrivilege(p)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, v := range grantTo {
+		g.AddRole(v)
+	}
+
+	return g, nil
+}
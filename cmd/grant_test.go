@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+)
+
+// Unit test grantMigration() and revokeMigration()
+func TestGrantAndRevokeMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetGrantFlagsHelper()
+
+	grantTo = []string{"appUser"}
+	err := grantMigration(sqlt.TableObject)(cmd, []string{"users,orders", "select,insert", "update"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	grantTo = []string{"public"}
+	grantAllInSchema = true
+	err = revokeMigration(sqlt.SequenceObject)(cmd, []string{"billing", "all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resetGrantFlagsHelper()
+	grantTo = []string{"readers"}
+	grantOn = "tables"
+	grantForRole = "appOwner"
+	err = grantMigration("")(cmd, []string{"public", "select"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(3, t)
+
+	cases := [][]string{
+		{"grant_on_table_users_orders", ms[0].Name()},
+		{"GRANT SELECT, INSERT, UPDATE ON TABLE users, orders TO app_user;", ms[0].UpSQL()},
+		{"REVOKE SELECT, INSERT, UPDATE ON TABLE users, orders FROM app_user;", ms[0].DownSQL()},
+		{"revoke_on_all_sequences_in_billing", ms[1].Name()},
+		{"REVOKE ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA billing FROM PUBLIC;", ms[1].UpSQL()},
+		{"GRANT ALL PRIVILEGES ON ALL SEQUENCES IN SCHEMA billing TO PUBLIC;", ms[1].DownSQL()},
+		{"grant_default_privileges_in_public", ms[2].Name()},
+		{"ALTER DEFAULT PRIVILEGES FOR ROLE app_owner IN SCHEMA public\n\tGRANT SELECT ON TABLES TO readers;", ms[2].UpSQL()},
+		{"ALTER DEFAULT PRIVILEGES FOR ROLE app_owner IN SCHEMA public\n\tREVOKE SELECT ON TABLES FROM readers;", ms[2].DownSQL()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+
+	// A privilege must be valid for the object type.
+	err = grantMigration(sqlt.SchemaObject)(cmd, []string{"billing", "select"})
+	if err == nil {
+		t.Error("want error for an invalid privilege; got nil")
+	}
+}
+
+// resetGrantFlagsHelper resets the variables bound to grant flags.
+func resetGrantFlagsHelper() {
+	grantTo = nil
+	grantWithGrantOption, grantAllInSchema = false, false
+	grantOn, grantForRole = "", ""
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/spf13/viper"
+)
+
+// Migration events that trigger the hooks set by "migrate.hooks.<event>" in
+// the config file.
+const (
+	// hookBeforeMigrate hooks run before the staged migrations are executed.
+	hookBeforeMigrate string = "before_migrate"
+
+	// hookAfterEach hooks run after each migration is executed. If the
+	// migration has a transaction, each SQL hook runs in it, in a savepoint,
+	// and the command hooks run after the transaction is committed.
+	hookAfterEach string = "after_each"
+
+	// hookAfterMigrate hooks run after all staged migrations are executed.
+	hookAfterMigrate string = "after_migrate"
+
+	// hookOnError hooks run when a migration, or a hook, fails.
+	hookOnError string = "on_error"
+)
+
+// Policies for a failed hook, set by "on_failure" for each hook or by
+// "migrate.hook_failure" in the config file.
+const (
+	// hookFailureAbort fails the migration. It is the default.
+	hookFailureAbort string = "abort"
+
+	// hookFailureWarn prints a warning and continues the migration.
+	hookFailureWarn string = "warn"
+)
+
+// hook is an SQL file executed in the migration session, or an external
+// command executed by the shell, e.g.
+//
+//	migrate:
+//	  hooks:
+//	    after_each:
+//	      - sql: hooks/analyze.sql
+//	    after_migrate:
+//	      - command: pg_dump --schema-only app_development > schema.sql
+//	        on_failure: warn
+type hook struct {
+	SQL       string `mapstructure:"sql"`
+	Command   string `mapstructure:"command"`
+	OnFailure string `mapstructure:"on_failure"`
+}
+
+// String returns the SQL file or the command of the hook.
+func (h hook) String() string {
+	if h.SQL != "" {
+		return h.SQL
+	}
+	return h.Command
+}
+
+// failurePolicy returns the policy for a failure of the hook.
+func (h hook) failurePolicy() (string, error) {
+	p := h.OnFailure
+	if p == "" {
+		p = viper.GetString("migrate.hook_failure")
+	}
+	switch p {
+	case "", hookFailureAbort:
+		return hookFailureAbort, nil
+	case hookFailureWarn:
+		return hookFailureWarn, nil
+	}
+	return "", fmt.Errorf("invalid hook failure policy %q: want %q or %q", p, hookFailureAbort, hookFailureWarn)
+}
+
+// validate returns an error unless the hook has either an SQL file or a
+// command.
+func (h hook) validate() error {
+	if h.SQL != "" && h.Command != "" {
+		return fmt.Errorf("a hook has both sql %q and command %q", h.SQL, h.Command)
+	}
+	if h.SQL == "" && h.Command == "" {
+		return errors.New("a hook has neither sql nor command")
+	}
+
+	return nil
+}
+
+// isSQL reports whether the hook is an SQL file.
+func (h hook) isSQL() bool {
+	return h.SQL != ""
+}
+
+// isCommand reports whether the hook is an external command.
+func (h hook) isCommand() bool {
+	return h.Command != ""
+}
+
+// run executes the hook. An SQL hook is executed by execSQL; a command hook
+// receives env as environment variables in addition to those of monarch, and
+// its standard output is written to commandOutput().
+func (h hook) run(ctx context.Context, execSQL func(string) error, env []string) error {
+	if h.SQL != "" {
+		sql, err := ioutil.ReadFile(h.SQL)
+		if err != nil {
+			return err
+		}
+		return execSQL(string(sql))
+	}
+
+	c := exec.CommandContext(ctx, "sh", "-c", h.Command)
+	c.Env = append(os.Environ(), env...)
+	c.Stdout = commandOutput()
+	c.Stderr = os.Stderr
+
+	return c.Run()
+}
+
+// loadHooks reads in the hooks for a migration event from the config file.
+func loadHooks(event string) ([]hook, error) {
+	hooks := make([]hook, 0)
+	err := viper.UnmarshalKey("migrate.hooks."+event, &hooks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s hooks in the config file: %s", event, err)
+	}
+
+	return hooks, nil
+}
+
+// runHooks runs the hooks for a migration event in order. A failed hook stops
+// the hooks and fails the migration unless its failure policy is to warn. A
+// failed on_error hook only prints a warning, so that it does not hide the
+// error that triggered it.
+func runHooks(ctx context.Context, name string, execSQL func(string) error, env []string) error {
+	return runHooksIf(ctx, name, execSQL, env, nil)
+}
+
+// runHooksIf runs the hooks for a migration event like runHooks, but skips
+// the valid hooks for which only returns false. If only is nil, it runs all
+// hooks.
+func runHooksIf(ctx context.Context, name string, execSQL func(string) error, env []string, only func(hook) bool) error {
+	hooks, err := loadHooks(name)
+	if err != nil {
+		return err
+	}
+
+	env = append([]string{"MONARCH_HOOK=" + name}, env...)
+	for _, h := range hooks {
+		err = h.validate()
+		if err != nil {
+			return fmt.Errorf("invalid %s hook in the config file: %w", name, err)
+		}
+		if only != nil && !only(h) {
+			continue
+		}
+		policy, err := h.failurePolicy()
+		if err != nil {
+			return err
+		}
+
+		err = h.run(ctx, execSQL, env)
+		if err == nil {
+			emit(event{
+				Event:   "hook_ran",
+				Message: fmt.Sprintf("Ran %s hook %s", name, h),
+				Hook:    name,
+				File:    h.String(),
+			})
+			continue
+		}
+		if policy == hookFailureWarn || name == hookOnError {
+			emit(event{
+				Event:   "hook_failed",
+				Message: fmt.Sprintf("Warning: %s hook %s failed: %s", name, h, err),
+				Hook:    name,
+				File:    h.String(),
+				Error:   err.Error(),
+			})
+			continue
+		}
+
+		return fmt.Errorf("%s hook %s failed: %w", name, h, err)
+	}
+
+	return nil
+}
+
+// migrationHookEnv returns the environment variables that describe migration
+// m to a command hook. A repeatable migration has version 0.
+func migrationHookEnv(m migration.Migration) []string {
+	return []string{
+		"MONARCH_MIGRATION_VERSION=" + strconv.FormatInt(m.Version(), 10),
+		"MONARCH_MIGRATION_NAME=" + m.Name(),
+		"MONARCH_MIGRATION_KEY=" + m.Key(),
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/spf13/viper"
+)
+
+// Unit test runHooks()
+func TestRunHooks(t *testing.T) {
+	const hooksDir = "hooks"
+	fileutil.MkdirP(hooksDir)
+	defer os.RemoveAll(hooksDir) // Do cleanup
+	err := fileutil.CreateAndWriteString(hooksDir+"/analyze.sql", "ANALYZE;")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	viper.Set("migrate.hooks", map[string]interface{}{
+		hookAfterEach: []map[string]interface{}{
+			{"sql": hooksDir + "/analyze.sql"},
+			{"command": `test "$MONARCH_HOOK $MONARCH_MIGRATION_NAME" = "after_each create_table_users"`},
+		},
+		hookAfterMigrate: []map[string]interface{}{
+			{"command": "exit 1", "on_failure": hookFailureWarn},
+			{"command": "exit 2"},
+		},
+	})
+	defer viper.Set("migrate.hooks", nil)
+
+	var m migration.Migration
+	m.SetName("create_table_users")
+	m.SetVersion(1)
+
+	// SQL hooks are executed by execSQL, and commands receive the
+	// environment variables.
+	executed := make([]string, 0)
+	execSQL := func(sql string) error {
+		executed = append(executed, sql)
+		return nil
+	}
+	err = runHooks(context.Background(), hookAfterEach, execSQL, migrationHookEnv(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(executed) != 1 || executed[0] != "ANALYZE;" {
+		t.Errorf("want [ANALYZE;]; got %q", executed)
+	}
+
+	// A failed hook that warns is skipped; a failed hook that aborts fails.
+	err = runHooks(context.Background(), hookAfterMigrate, execSQL, nil)
+	if err == nil {
+		t.Errorf("want error; got nil")
+	}
+
+	// A failed SQL hook fails.
+	execSQL = func(sql string) error {
+		return errors.New("syntax error")
+	}
+	err = runHooks(context.Background(), hookAfterEach, execSQL, migrationHookEnv(m))
+	if err == nil {
+		t.Errorf("want error; got nil")
+	}
+
+	// Only the command hooks run, so the failing SQL hook is skipped.
+	err = runHooksIf(context.Background(), hookAfterEach, execSQL, migrationHookEnv(m), hook.isCommand)
+	if err != nil {
+		t.Errorf("want nil; got %v", err)
+	}
+
+	// Events without hooks do nothing.
+	err = runHooks(context.Background(), hookBeforeMigrate, execSQL, nil)
+	if err != nil {
+		t.Errorf("want nil; got %v", err)
+	}
+}
+
+// Unit test hook.failurePolicy()
+func TestHookFailurePolicy(t *testing.T) {
+	cases := []struct {
+		onFailure string
+		global    string
+		exp       string
+	}{
+		{"", "", hookFailureAbort},
+		{"", hookFailureWarn, hookFailureWarn},
+		{hookFailureAbort, hookFailureWarn, hookFailureAbort},
+	}
+	defer viper.Set("migrate.hook_failure", nil)
+	for _, c := range cases {
+		viper.Set("migrate.hook_failure", c.global)
+		act, err := hook{OnFailure: c.onFailure}.failurePolicy()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.exp != act {
+			t.Errorf("want %q; got %q", c.exp, act)
+		}
+	}
+
+	_, err := hook{OnFailure: "retry"}.failurePolicy()
+	if err == nil {
+		t.Errorf("want error; got nil")
+	}
+}
+
+// Unit test hook.validate()
+func TestHookValidate(t *testing.T) {
+	cases := []struct {
+		h   hook
+		err bool
+	}{
+		{hook{SQL: "hooks/analyze.sql"}, false},
+		{hook{Command: "true"}, false},
+		{hook{SQL: "hooks/analyze.sql", Command: "true"}, true},
+		{hook{OnFailure: hookFailureWarn}, true},
+	}
+	for _, c := range cases {
+		err := c.h.validate()
+		if c.err != (err != nil) {
+			t.Errorf("%+v: want error %t; got %v", c.h, c.err, err)
+		}
+	}
+}
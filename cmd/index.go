@@ -3,53 +3,244 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/kevinsapp/monarch/pkg/migration"
 	"github.com/kevinsapp/monarch/pkg/sqlt"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Index options set by command flags.
+var (
+	idxName         string
+	idxUnique       bool
+	idxMethod       string
+	idxWhere        string
+	idxInclude      []string
+	idxConcurrently bool
 )
 
 func init() {
 	createCmd.AddCommand(createIndexCmd)
+	dropCmd.AddCommand(dropIndexCmd)
+
+	addIndexFlags(createIndexCmd.Flags())
+	addIndexFlags(dropIndexCmd.Flags())
+}
+
+// addIndexFlags defines the flags shared by the index commands.
+func addIndexFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&idxName, "name", "", "index name (default is [tablename]_[columns]_mnrk_idx)")
+	fs.BoolVar(&idxUnique, "unique", false, "create a UNIQUE index")
+	fs.StringVar(&idxMethod, "using", "", "index access method: btree, hash, gist, spgist, gin or brin")
+	fs.StringVar(&idxWhere, "where", "", "predicate for a partial index")
+	fs.StringSliceVar(&idxInclude, "include", nil, "non-key columns to INCLUDE in a covering index")
+	fs.BoolVar(&idxConcurrently, "concurrently", false, "build or drop the index CONCURRENTLY, outside of a transaction")
 }
 
 // createIndexCmd generates a migration file to create an index on a table column.
 var createIndexCmd = &cobra.Command{
-	Use:   "index [tablename] [columnname]",
-	Short: "Generate a migration file to create an index on a table column.",
-	RunE:  createIndexMigration,
+	Use:   "index [tablename] [ [column[:option ...]] ... ]",
+	Short: "Generate a migration file to create an index on one or more table columns.",
+	Long: `Generate a migration file to create an index on one or more table columns.
+	Each column may be followed by colon-separated options: asc, desc, nulls-first,
+	nulls-last, or the name of an operator class. For example:
+
+	  monarch g m create index users lastName:desc:nulls-last firstName
+	  monarch g m create index users "(lower(email))" --unique
+	  monarch g m create index posts title:gin_trgm_ops --using gin
+
+	An argument that starts with "(" is an expression rather than a column name.
+
+	With --concurrently, the index is built without blocking writes to the table, and the
+	migration is executed outside of a transaction. If the build fails, the INVALID index
+	it leaves behind is dropped by "db migrate".`,
+	RunE: createIndexMigration,
+}
+
+// dropIndexCmd generates a migration file to drop an index from a table.
+var dropIndexCmd = &cobra.Command{
+	Use:   "index [tablename] [ [column[:option ...]] ... ]",
+	Short: "Generate a migration file to drop an index.",
+	Long: `Generate a migration file to drop an index. The arguments and flags should match those
+	used to create the index so that the "down" migration can restore it.`,
+	RunE: dropIndexMigration,
 }
 
 // createIndexMigration creates a migration file to create an index on a table column.
 func createIndexMigration(cmd *cobra.Command, args []string) error {
-	// Caller should supply a table name as the first argument and a column name
-	// as the second argument.
-	if len(args) < 2 {
-		return errors.New("requires a tablename argument followed by a columnname argument")
+	// Set index data.
+	idx, err := indexFromArgs(args)
+	if err != nil {
+		return err
 	}
 
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(idx, sqlt.CreateIndexTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(idx, sqlt.DropIndexTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	migrationName := fmt.Sprintf("CreateIndexOn_%s_%s", idx.TableName(), idx.ColumnLabel())
+	err = createMigrationTx(migrationName, upSQL, downSQL, indexTransaction(idx))
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// dropIndexMigration creates a migration file to drop an index from a table.
+func dropIndexMigration(cmd *cobra.Command, args []string) error {
 	// Set index data.
-	idx := new(sqlt.Index)
-	idx.SetTableName(args[0])
-	idx.SetColumnName(args[1])
+	idx, err := indexFromArgs(args)
+	if err != nil {
+		return err
+	}
 
 	// Process SQL template for "up" migration.
-	upSQL, err := sqlt.ProcessTmpl(idx, sqlt.CreateDefaultIndexTmpl)
+	upSQL, err := sqlt.ProcessTmpl(idx, sqlt.DropIndexTmpl)
 	if err != nil {
 		return err
 	}
 
 	// Process SQL template for "down" migration.
-	downSQL, err := sqlt.ProcessTmpl(idx, sqlt.DropIndexTmpl)
+	downSQL, err := sqlt.ProcessTmpl(idx, sqlt.CreateIndexTmpl)
 	if err != nil {
 		return err
 	}
 
 	// Create migration file.
-	migrationName := fmt.Sprintf("CreateIndexOn_%s_%s", idx.TableName(), idx.ColumnName())
-	err = createMigration(migrationName, upSQL, downSQL)
+	migrationName := fmt.Sprintf("DropIndexFrom_%s_%s", idx.TableName(), idx.ColumnLabel())
+	err = createMigrationTx(migrationName, upSQL, downSQL, indexTransaction(idx))
 	if err != nil {
 		return err
 	}
 
 	return err
 }
+
+// indexTransaction returns the transaction mode for a migration that creates
+// or drops an index.
+func indexTransaction(idx *sqlt.Index) string {
+	if idx.Concurrently() {
+		return migration.TransactionNone
+	}
+	return migration.TransactionDefault
+}
+
+// indexFromArgs configures an index from command arguments and flags.
+func indexFromArgs(args []string) (*sqlt.Index, error) {
+	// Caller should supply a table name as the first argument and one or more
+	// column names as the following arguments.
+	if len(args) < 2 {
+		return nil, errors.New("requires a tablename argument followed by one or more columnname arguments")
+	}
+
+	idx := new(sqlt.Index)
+	idx.SetTableName(args[0])
+	for _, v := range args[1:] {
+		col, err := parseIndexColumn(v)
+		if err != nil {
+			return nil, err
+		}
+		idx.AddColumn(col)
+	}
+
+	if idxName != "" {
+		idx.SetName(idxName)
+	}
+	idx.SetUnique(idxUnique)
+	idx.SetWhere(idxWhere)
+	idx.SetConcurrently(idxConcurrently)
+	for _, v := range idxInclude {
+		idx.AddInclude(v)
+	}
+
+	switch m := strings.ToLower(idxMethod); m {
+	case "", "btree", "hash", "gist", "spgist", "gin", "brin":
+		idx.SetMethod(m)
+	default:
+		return nil, fmt.Errorf("unsupported index access method %q", idxMethod)
+	}
+	if idx.Unique() && idx.Method() != "" && idx.Method() != "btree" {
+		return nil, fmt.Errorf("a unique index requires the btree access method, not %q", idx.Method())
+	}
+
+	return idx, nil
+}
+
+// parseIndexColumn parses an index column argument of the form
+// "name[:option ...]" or "(expression)[:option ...]".
+func parseIndexColumn(spec string) (sqlt.IndexColumn, error) {
+	col := sqlt.IndexColumn{}
+
+	// Split the column name or expression from its options.
+	var opts string
+	if strings.HasPrefix(spec, "(") {
+		end := closingParen(spec)
+		if end < 0 {
+			return col, fmt.Errorf("index expression %q has unbalanced parentheses", spec)
+		}
+		col.SetExpression(spec[:end+1])
+		opts = spec[end+1:]
+		if opts != "" && !strings.HasPrefix(opts, ":") {
+			return col, fmt.Errorf("index expression %q should be followed by :option", spec)
+		}
+		opts = strings.TrimPrefix(opts, ":")
+	} else {
+		nameOpts := strings.SplitN(spec, ":", 2)
+		col.SetName(nameOpts[0])
+		if len(nameOpts) > 1 {
+			opts = nameOpts[1]
+		}
+	}
+
+	// Apply options.
+	if opts == "" {
+		return col, nil
+	}
+	for _, o := range strings.Split(opts, ":") {
+		switch strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(o)) {
+		case "asc", "desc":
+			col.SetOrder(o)
+		case "nullsfirst":
+			col.SetNulls("first")
+		case "nullslast":
+			col.SetNulls("last")
+		case "":
+			return col, fmt.Errorf("index column %q has an empty option", spec)
+		default:
+			col.SetOpClass(o)
+		}
+	}
+
+	return col, nil
+}
+
+// closingParen returns the index of the parenthesis that closes the one at
+// the start of s, or -1 if there is none.
+func closingParen(s string) int {
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
@@ -65,3 +65,150 @@ func TestCreateIndexMigration(t *testing.T) {
 		t.Errorf("\nwant %q;\n got %q\n", exp, act)
 	}
 }
+
+// Unit test createIndexMigration() with multiple columns, expressions and options.
+func TestCreateIndexMigrationOptions(t *testing.T) {
+	cases := []struct {
+		args     []string
+		setFlags func()
+		upSQL    string
+		downSQL  string
+	}{
+		{
+			[]string{"users", "lastName:desc:nulls-last", "firstName"},
+			func() {},
+			`CREATE INDEX users_last_name_first_name_mnrk_idx ON users (last_name DESC NULLS LAST, first_name);`,
+			`DROP INDEX IF EXISTS users_last_name_first_name_mnrk_idx;`,
+		},
+		{
+			[]string{"users", "(lower(email))"},
+			func() {
+				idxUnique = true
+				idxWhere = "deleted_at IS NULL"
+				idxInclude = []string{"accountID"}
+			},
+			`CREATE UNIQUE INDEX users_lower_email_mnrk_idx ON users ((lower(email))) INCLUDE (account_id) WHERE deleted_at IS NULL;`,
+			`DROP INDEX IF EXISTS users_lower_email_mnrk_idx;`,
+		},
+		{
+			[]string{"posts", "title:gin_trgm_ops"},
+			func() {
+				idxMethod = "GIN"
+				idxName = "postsTitleTrgm"
+			},
+			`CREATE INDEX posts_title_trgm ON posts USING gin (title gin_trgm_ops);`,
+			`DROP INDEX IF EXISTS posts_title_trgm;`,
+		},
+	}
+
+	for _, c := range cases {
+		// Create a migrations directory.
+		cmd := &cobra.Command{}
+		mkdirMigrations(cmd, nil)
+
+		// Run createIndexMigration() with flags set.
+		c.setFlags()
+		err := createIndexMigration(cmd, c.args)
+		resetIndexFlagsHelper()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := readMigrationsHelper(1, t)[0]
+
+		// Verify that the upSQL is as expected.
+		if exp, act := c.upSQL, m.UpSQL(); exp != act {
+			t.Errorf("\nwant %q;\n got %q\n", exp, act)
+		}
+
+		// Verify that the downSQL is as expected.
+		if exp, act := c.downSQL, m.DownSQL(); exp != act {
+			t.Errorf("\nwant %q;\n got %q\n", exp, act)
+		}
+
+		os.RemoveAll(migrationsDir) // Do cleanup
+	}
+}
+
+// Unit test createIndexMigration() and dropIndexMigration() with --concurrently.
+func TestIndexMigrationConcurrently(t *testing.T) {
+	createSQL := `CREATE UNIQUE INDEX CONCURRENTLY users_email_mnrk_idx ON users (email);`
+	dropSQL := `DROP INDEX CONCURRENTLY IF EXISTS users_email_mnrk_idx;`
+
+	cases := []struct {
+		run     func(*cobra.Command, []string) error
+		upSQL   string
+		downSQL string
+	}{
+		{createIndexMigration, createSQL, dropSQL},
+		{dropIndexMigration, dropSQL, createSQL},
+	}
+
+	for _, c := range cases {
+		// Create a migrations directory.
+		cmd := &cobra.Command{}
+		mkdirMigrations(cmd, nil)
+
+		// Run the command with flags set.
+		idxConcurrently = true
+		idxUnique = true
+		err := c.run(cmd, []string{"users", "email"})
+		resetIndexFlagsHelper()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := readMigrationsHelper(1, t)[0]
+
+		// Verify that the upSQL is as expected.
+		if exp, act := c.upSQL, m.UpSQL(); exp != act {
+			t.Errorf("\nwant %q;\n got %q\n", exp, act)
+		}
+
+		// Verify that the downSQL is as expected.
+		if exp, act := c.downSQL, m.DownSQL(); exp != act {
+			t.Errorf("\nwant %q;\n got %q\n", exp, act)
+		}
+
+		// Verify that the migration runs outside of a transaction.
+		if exp, act := migration.TransactionNone, m.Transaction(); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+
+		os.RemoveAll(migrationsDir) // Do cleanup
+	}
+}
+
+// Unit test indexFromArgs() with invalid arguments.
+func TestIndexFromArgsErrors(t *testing.T) {
+	cases := []struct {
+		args     []string
+		setFlags func()
+	}{
+		{[]string{"users"}, func() {}},
+		{[]string{"users", "(lower(email)"}, func() {}},
+		{[]string{"users", "(lower(email))desc"}, func() {}},
+		{[]string{"users", "email::desc"}, func() {}},
+		{[]string{"users", "email"}, func() { idxMethod = "rtree" }},
+		{[]string{"users", "email"}, func() { idxMethod = "gin"; idxUnique = true }},
+	}
+
+	for _, c := range cases {
+		c.setFlags()
+		_, err := indexFromArgs(c.args)
+		resetIndexFlagsHelper()
+		if err == nil {
+			t.Errorf("want error for args %q; got nil", c.args)
+		}
+	}
+}
+
+// resetIndexFlagsHelper restores the index flags to their defaults.
+func resetIndexFlagsHelper() {
+	idxName = ""
+	idxUnique = false
+	idxMethod = ""
+	idxWhere = ""
+	idxInclude = nil
+	idxConcurrently = false
+}
@@ -3,44 +3,124 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// defaultSchemaVersionsTable is the name of the table that records the
+// versions of executed migrations, unless "migrate.schema_versions_table" is
+// set in the config file.
+const defaultSchemaVersionsTable = "schema_versions"
+
+// Migrate options set by command flags.
+var (
+	migrateCheck  bool
+	migrateDryRun bool
+	migrateEnv    string
+	migrateVars   []string
 )
 
 func init() {
 	dbCmd.AddCommand(migrateDBCmd)
+
+	migrateDBCmd.Flags().BoolVar(&migrateCheck, "check", false, "report pending migrations without executing them, and exit with 3 if there are any")
+	migrateDBCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "print the rendered SQL of pending migrations without executing them")
+	migrateDBCmd.Flags().StringVar(&migrateEnv, "env", "development", "config file section of the database to migrate")
+	migrateDBCmd.Flags().StringArrayVar(&migrateVars, "var", nil, "template variable as key=value; overrides the vars of the config file section")
 }
 
 // migrateCmd ...
 var migrateDBCmd = &cobra.Command{
 	Use:   "migrate",
 	Short: `Migrate a database.`,
-	RunE:  migrateDB,
+	Long: `Migrate a database by executing the migrations that are later than its schema version.
+	Repeatable migrations, in files named R_<name>.sql, are executed after them, in order of name,
+	whenever their content has changed since they were last executed. A repeatable migration
+	should be idempotent, e.g. CREATE OR REPLACE VIEW, and needs no migration delimiter.
+
+	Hooks set by "migrate.hooks" in the config file run before_migrate, after_each migration,
+	after_migrate, and on_error. A hook is an SQL file ("sql") executed in the migration session,
+	or a shell command ("command") that receives MONARCH_HOOK, MONARCH_SCHEMA_VERSION,
+	MONARCH_MIGRATION_VERSION, MONARCH_MIGRATION_NAME and MONARCH_ERROR as environment variables.
+	A failed hook fails the migration, unless its "on_failure", or "migrate.hook_failure", is warn.
+	Hooks run only when there are migrations to execute. The after_each SQL hooks of a migration
+	run in its transaction; its after_each command hooks run after the transaction is committed.
+
+	A migration with the "-- monarch:template" directive is a text/template whose placeholders,
+	e.g. {{.reader_role}}, are resolved from the "vars" of the --env section of the config file
+	and from --var flags. Variable names are case-insensitive and lowercased. An undefined
+	variable is an error. With --dry-run, the rendered SQL is printed instead of executed.
+	Neither --dry-run nor --check writes to the database.`,
+	RunE: migrateDB,
 }
 
 // migrateDB establishes a connection to the database and executes "up"
 // migrations
 func migrateDB(cmd *cobra.Command, args []string) error {
 	var srv dbServer
-	srv.initFromConfig()
+	srv.initFromConfigEnv(migrateEnv)
+	if srv.host == "" {
+		return fmt.Errorf("no %q section in the config file", migrateEnv)
+	}
 
 	// Connect to the database server.
 	ctx := context.Background()
-	pool, err := pgxpool.Connect(ctx, srv.dsn())
+	pool, err := connectMigrationPool(ctx, srv)
 	if err != nil {
 		return err
 	}
 	defer pool.Close()
 
+	// Print the rendered SQL of pending migrations without executing them.
+	if migrateDryRun {
+		_, ms, err := stageMigrations(ctx, pool, migrateEnv, true)
+		if err != nil {
+			return err
+		}
+		for _, m := range ms {
+			emit(event{
+				Event:     "migration_rendered",
+				Message:   fmt.Sprintf("-- %s\n%s\n", m.Key(), m.UpSQL()),
+				Migration: m.Key(),
+				Version:   m.Version(),
+				SQL:       m.UpSQL(),
+			})
+		}
+		return nil
+	}
+
+	// Report pending migrations without executing them.
+	if migrateCheck {
+		_, ms, err := stageMigrations(ctx, pool, migrateEnv, true)
+		if err != nil {
+			return err
+		}
+		if len(ms) > 0 {
+			return withExitCode(exitPendingMigrations, fmt.Errorf("database %q has %d pending migration(s)", srv.dbName, len(ms)))
+		}
+		emit(event{
+			Event:    "migrations_checked",
+			Message:  fmt.Sprintf("Database %q has no pending migrations.", srv.dbName),
+			Database: srv.dbName,
+		})
+		return nil
+	}
+
 	// Timestamp command start.
 	start := time.Now()
 
 	// Up migrate the schema.
-	err = upMigrateSchema(ctx, pool)
+	err = upMigrateSchema(ctx, pool, migrateEnv)
 	if err != nil {
 		return err
 	}
@@ -48,45 +128,189 @@ func migrateDB(cmd *cobra.Command, args []string) error {
 	// Timestamp command end.
 	duration := time.Since(start)
 
-	fmt.Printf("Database %q migrated. Command completed in %s.\n", srv.dbName, duration)
+	emit(event{
+		Event:      "database_migrated",
+		Message:    fmt.Sprintf("Database %q migrated. Command completed in %s.", srv.dbName, duration),
+		Database:   srv.dbName,
+		DurationMS: durationMS(duration),
+	})
 
 	return nil
 }
 
-// upMigrateSchema executes up migrates later than the last version in the
-// schema_versions table.
-func upMigrateSchema(ctx context.Context, pool *pgxpool.Pool) error {
+// connectMigrationPool connects to a database to migrate it. The search_path
+// of each session is set by "migrate.search_path" in the config file.
+func connectMigrationPool(ctx context.Context, srv dbServer) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(srv.dsn())
+	if err != nil {
+		return nil, err
+	}
+	if sp := viper.GetString("migrate.search_path"); sp != "" {
+		cfg.ConnConfig.RuntimeParams["search_path"] = sp
+	}
+
+	return pgxpool.ConnectConfig(ctx, cfg)
+}
+
+// stageMigrations returns the schema version and the "up" migrations to
+// execute: those later than the last version in the schema_versions table,
+// and the repeatable migrations that have changed since they were executed.
+// Template migrations are rendered with the variables of the config file
+// section env. If readOnly is true, stageMigrations does not create the
+// schema_versions and schema_repeatable_migrations tables, and treats a
+// missing table as empty.
+func stageMigrations(ctx context.Context, pool *pgxpool.Pool, env string, readOnly bool) (int64, []migration.Migration, error) {
 	// Create the schema_migrations table if it does not exist.
-	err := createSchemaVersionsTable(ctx, pool)
+	found, err := prepareTable(ctx, pool, schemaVersionsTable(), createSchemaVersionsTable, readOnly)
 	if err != nil {
-		return err
+		return 0, nil, err
 	}
 
 	// Fetch latest schema version from schema_versions table.
-	ver, err := fetchSchemaVersion(ctx, pool)
+	var ver int64
+	if found {
+		ver, err = fetchSchemaVersion(ctx, pool)
+		if err != nil {
+			return ver, nil, err
+		}
+	}
+
+	// Stage the "up" migrations later than schema version, and the
+	// repeatable migrations that have changed since they were executed.
+	ms, err := migration.LoadPending(ver, migrationsDir)
+	if err != nil {
+		return ver, nil, err
+	}
+	vars, err := migrationVars(env)
+	if err != nil {
+		return ver, nil, err
+	}
+	err = renderMigrations(ms, vars)
+	if err != nil {
+		return ver, nil, err
+	}
+	found, err = prepareTable(ctx, pool, repeatableMigrationsTable(), createRepeatableMigrationsTable, readOnly)
+	if err != nil {
+		return ver, nil, err
+	}
+	checksums := make(map[string]string)
+	if found {
+		checksums, err = fetchRepeatableChecksums(ctx, pool)
+		if err != nil {
+			return ver, nil, err
+		}
+	}
+	ms = changedMigrations(ms, checksums)
+
+	for _, m := range ms {
+		msg := fmt.Sprintf("Staged %q migration version: %d", "up", m.Version())
+		if m.Repeatable() {
+			msg = fmt.Sprintf("Staged %q repeatable migration: %s", "up", m.Name())
+		}
+		emit(event{
+			Event:     "migration_staged",
+			Message:   msg,
+			Migration: m.Key(),
+			Version:   m.Version(),
+		})
+	}
+
+	return ver, ms, nil
+}
+
+// upMigrateSchema executes up migrates later than the last version in the
+// schema_versions table, rendered with the variables of the config file
+// section env.
+func upMigrateSchema(ctx context.Context, pool *pgxpool.Pool, env string) error {
+	ver, ms, err := stageMigrations(ctx, pool, env, false)
 	if err != nil {
 		return err
 	}
+	if len(ms) == 0 {
+		return nil
+	}
 
-	// Stage the "up" migrations later than schema version
-	ms, err := migration.LoadAllLaterThan(ver, migrationsDir)
+	// Acquire a connection, so that the hooks and the migrations run in the
+	// same session, e.g. after a before_migrate hook that sets lock_timeout.
+	conn, err := pool.Acquire(ctx)
 	if err != nil {
 		return err
 	}
+	defer conn.Release()
 
-	// Execute migrations.
-	err = execUpMigrations(ctx, pool, ms)
+	// Execute migrations between the before_migrate and after_migrate hooks.
+	execSQL := func(sql string) error {
+		_, err := conn.Exec(ctx, sql)
+		return err
+	}
+	err = runHooks(ctx, hookBeforeMigrate, execSQL, schemaVersionHookEnv(ver))
+	if err == nil {
+		err = execUpMigrations(ctx, conn, ms)
+	}
+	if err == nil {
+		for _, m := range ms {
+			if !m.Repeatable() {
+				ver = m.Version()
+			}
+		}
+		err = runHooks(ctx, hookAfterMigrate, execSQL, schemaVersionHookEnv(ver))
+	}
+	if err != nil {
+		env := append(schemaVersionHookEnv(ver), "MONARCH_ERROR="+err.Error())
+		runHooks(ctx, hookOnError, execSQL, env)
+	}
 
 	return err
 }
 
-// createSchemaVersionsTable creates a schema_versions table if it does not
-// already exist.
+// prepareTable creates a table by calling create, unless readOnly is true, in
+// which case it only checks whether the table exists, so that nothing is
+// written to the database. prepareTable reports whether the table exists.
+func prepareTable(ctx context.Context, pool *pgxpool.Pool, table pgx.Identifier, create func(context.Context, *pgxpool.Pool) error, readOnly bool) (bool, error) {
+	if !readOnly {
+		return true, create(ctx, pool)
+	}
+
+	var exists bool
+	err := pool.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL;", table.Sanitize()).Scan(&exists)
+
+	return exists, err
+}
+
+// schemaVersionHookEnv returns the environment variable that gives a command
+// hook the schema version.
+func schemaVersionHookEnv(ver int64) []string {
+	return []string{"MONARCH_SCHEMA_VERSION=" + strconv.FormatInt(ver, 10)}
+}
+
+// schemaVersionsTable returns the quoted, and possibly schema-qualified, name
+// of the schema_versions table set by "migrate.schema_versions_table" in the
+// config file.
+func schemaVersionsTable() pgx.Identifier {
+	name := viper.GetString("migrate.schema_versions_table")
+	if name == "" {
+		name = defaultSchemaVersionsTable
+	}
+
+	return pgx.Identifier(strings.Split(name, "."))
+}
+
+// createSchemaVersionsTable creates a schema_versions table, and its schema,
+// if it does not already exist.
 func createSchemaVersionsTable(ctx context.Context, pool *pgxpool.Pool) error {
-	sql := `CREATE TABLE IF NOT EXISTS schema_versions (
+	table := schemaVersionsTable()
+	if len(table) > 1 {
+		schema := pgx.Identifier(table[:len(table)-1]).Sanitize()
+		_, err := pool.Exec(ctx, "CREATE SCHEMA IF NOT EXISTS "+schema+";")
+		if err != nil {
+			return err
+		}
+	}
+
+	sql := `CREATE TABLE IF NOT EXISTS ` + table.Sanitize() + ` (
 		version bigint NOT NULL,
 		created_at timestamp(6) without time zone NOT NULL,
-		CONSTRAINT schema_migrations_pkey PRIMARY KEY (version)
+		PRIMARY KEY (version)
 	);`
 
 	_, err := pool.Exec(ctx, sql)
@@ -97,9 +321,125 @@ func createSchemaVersionsTable(ctx context.Context, pool *pgxpool.Pool) error {
 	return err
 }
 
+// insertSchemaVersionSQL returns a statement that inserts a migration version
+// into the schema_versions table.
+func insertSchemaVersionSQL() string {
+	return "INSERT INTO " + schemaVersionsTable().Sanitize() + " (version, created_at) VALUES ($1, now());"
+}
+
+// repeatableMigrationsTable returns the quoted name of the table that records
+// the checksums of executed repeatable migrations. It is in the schema of the
+// schema_versions table.
+func repeatableMigrationsTable() pgx.Identifier {
+	table := schemaVersionsTable()
+	name := append(pgx.Identifier{}, table[:len(table)-1]...)
+
+	return append(name, "schema_repeatable_migrations")
+}
+
+// createRepeatableMigrationsTable creates a schema_repeatable_migrations
+// table if it does not already exist. Its schema is created with the
+// schema_versions table.
+func createRepeatableMigrationsTable(ctx context.Context, pool *pgxpool.Pool) error {
+	sql := `CREATE TABLE IF NOT EXISTS ` + repeatableMigrationsTable().Sanitize() + ` (
+		name text NOT NULL,
+		checksum text NOT NULL,
+		executed_at timestamp(6) without time zone NOT NULL,
+		PRIMARY KEY (name)
+	);`
+
+	_, err := pool.Exec(ctx, sql)
+
+	return err
+}
+
+// fetchRepeatableChecksums fetches the checksum of each executed repeatable
+// migration, keyed by name.
+func fetchRepeatableChecksums(ctx context.Context, pool *pgxpool.Pool) (map[string]string, error) {
+	checksums := make(map[string]string)
+
+	rows, err := pool.Query(ctx, "SELECT name, checksum FROM "+repeatableMigrationsTable().Sanitize()+";")
+	if err != nil {
+		return checksums, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n, c string
+		err = rows.Scan(&n, &c)
+		if err != nil {
+			return checksums, err
+		}
+		checksums[n] = c
+	}
+
+	return checksums, rows.Err()
+}
+
+// migrationVars returns the variables of template migrations: the "vars" of
+// the config file section env, overridden by --var flags. Names are
+// lowercased, as viper lowercases the keys of the config file.
+func migrationVars(env string) (map[string]string, error) {
+	vars := viper.GetStringMapString(env + ".vars")
+	for _, kv := range migrateVars {
+		i := strings.Index(kv, "=")
+		if i < 1 {
+			return nil, fmt.Errorf("invalid --var %q: want key=value", kv)
+		}
+		vars[strings.ToLower(kv[:i])] = kv[i+1:]
+	}
+
+	return vars, nil
+}
+
+// renderMigrations resolves the placeholders of the "up" SQL of template
+// migrations with vars.
+func renderMigrations(ms []migration.Migration, vars map[string]string) error {
+	for i, m := range ms {
+		if !m.Template() {
+			continue
+		}
+		sql, err := sqlt.ProcessTmpl(vars, m.UpSQL())
+		if err != nil {
+			return fmt.Errorf("could not render migration %s: %w", m.Key(), err)
+		}
+		ms[i].SetUpSQL(sql)
+	}
+
+	return nil
+}
+
+// changedMigrations removes from ms the repeatable migrations whose checksums
+// are unchanged since they were executed.
+func changedMigrations(ms []migration.Migration, checksums map[string]string) []migration.Migration {
+	changed := make([]migration.Migration, 0, len(ms))
+	for _, m := range ms {
+		if m.Repeatable() && checksums[m.Name()] == m.Checksum() {
+			continue
+		}
+		changed = append(changed, m)
+	}
+
+	return changed
+}
+
+// recordMigrationSQL returns a statement, and its arguments, that records an
+// executed migration: the version of a versioned migration is inserted into
+// the schema_versions table, and the checksum of a repeatable migration is
+// upserted into the schema_repeatable_migrations table.
+func recordMigrationSQL(m migration.Migration) (string, []interface{}) {
+	if m.Repeatable() {
+		sql := "INSERT INTO " + repeatableMigrationsTable().Sanitize() + ` (name, checksum, executed_at) VALUES ($1, $2, now())
+		ON CONFLICT (name) DO UPDATE SET checksum = EXCLUDED.checksum, executed_at = EXCLUDED.executed_at;`
+		return sql, []interface{}{m.Name(), m.Checksum()}
+	}
+
+	return insertSchemaVersionSQL(), []interface{}{m.Version()}
+}
+
 // fetchSchemaVersion fetches latest schema version from schema_versions table.
 func fetchSchemaVersion(ctx context.Context, pool *pgxpool.Pool) (int64, error) {
-	r := pool.QueryRow(ctx, "SELECT max(version) FROM schema_versions;")
+	r := pool.QueryRow(ctx, "SELECT max(version) FROM "+schemaVersionsTable().Sanitize()+";")
 
 	var v pgtype.Int8
 	err := r.Scan(&v)
@@ -107,37 +447,211 @@ func fetchSchemaVersion(ctx context.Context, pool *pgxpool.Pool) (int64, error)
 		return v.Int, err
 	}
 
-	fmt.Printf("Current schema version is: %d\n", v.Int)
+	emit(event{
+		Event:   "schema_version",
+		Message: fmt.Sprintf("Current schema version is: %d", v.Int),
+		Version: v.Int,
+	})
 
 	return v.Int, err
 }
 
-// execUpMigrations executes all "up" migrations in contained in a
+// execUpMigrations executes all "up" migrations contained in a
+// []migration.Migration. Adjacent migrations are executed in a single database
+// transaction; if any of them fails, then the transaction is rolled back and
+// none of them are committed. A migration flagged to run in an isolated
+// transaction, or outside of a transaction, is executed on its own after the
+// migrations staged before it have been committed.
+func execUpMigrations(ctx context.Context, conn *pgxpool.Conn, ms []migration.Migration) error {
+	batch := make([]migration.Migration, 0, len(ms))
+	for _, m := range ms {
+		if m.Transaction() == migration.TransactionDefault {
+			batch = append(batch, m)
+			continue
+		}
+
+		// Commit the migrations staged before this one.
+		err := execUpMigrationsInTx(ctx, conn, batch)
+		if err != nil {
+			return err
+		}
+		batch = batch[:0]
+
+		// Execute the migration on its own.
+		if m.Transaction() == migration.TransactionNone {
+			err = execUpMigrationNoTx(ctx, conn, m)
+		} else {
+			err = execUpMigrationsInTx(ctx, conn, []migration.Migration{m})
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return execUpMigrationsInTx(ctx, conn, batch)
+}
+
+// execUpMigrationNoTx executes an "up" migration outside of a transaction,
+// one statement at a time, and then records its version. If a statement
+// fails, the statements before it are not rolled back, and any INVALID index
+// left behind by a failed CREATE INDEX CONCURRENTLY statement is dropped so
+// that the migration can be retried.
+func execUpMigrationNoTx(ctx context.Context, conn *pgxpool.Conn, m migration.Migration) error {
+	// Drop INVALID indexes left behind by an earlier attempt.
+	err := dropInvalidIndexes(ctx, conn, m)
+	if err != nil {
+		return err
+	}
+
+	// Execute each SQL statement from migration.
+	start := time.Now()
+	for _, stmt := range migration.SplitStatements(m.UpSQL()) {
+		_, err = conn.Exec(ctx, stmt)
+		if err != nil {
+			err = fmt.Errorf("could not execute migration %s: %w", m.Key(), err)
+
+			// Clean up after the failed statement.
+			cleanupErr := dropInvalidIndexes(ctx, conn, m)
+			if cleanupErr != nil {
+				return fmt.Errorf("%w; %s", err, cleanupErr)
+			}
+
+			return err
+		}
+	}
+
+	// Record the executed migration.
+	sql, args := recordMigrationSQL(m)
+	_, err = conn.Exec(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	emitMigrationApplied(m, time.Since(start))
+
+	// Run the after_each hooks.
+	execSQL := func(sql string) error {
+		_, err := conn.Exec(ctx, sql)
+		return err
+	}
+	err = runHooks(ctx, hookAfterEach, execSQL, migrationHookEnv(m))
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// concurrentIndexRegexp matches a CREATE INDEX CONCURRENTLY statement and
+// captures the index name and the table name.
+var concurrentIndexRegexp = regexp.MustCompile(`(?i)\bCREATE\s+(?:UNIQUE\s+)?INDEX\s+CONCURRENTLY\s+(?:IF\s+NOT\s+EXISTS\s+)?("[^"]+"|[\w.$]+)\s+ON\s+(?:ONLY\s+)?("[^"]+"|[\w.$]+)`)
+
+// concurrentIndexNames returns the names of the indexes created CONCURRENTLY
+// by a string of SQL. An index is created in the schema of its table, so an
+// index name is qualified with the schema of a schema-qualified table.
+func concurrentIndexNames(sql string) []string {
+	names := make([]string, 0)
+	for _, match := range concurrentIndexRegexp.FindAllStringSubmatch(sql, -1) {
+		name, table := match[1], match[2]
+		if i := strings.LastIndex(table, "."); i > 0 && !strings.HasPrefix(table, `"`) {
+			name = table[:i+1] + name
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// dropInvalidIndexes drops any INVALID index that is created CONCURRENTLY by
+// migration m. A failed CREATE INDEX CONCURRENTLY statement leaves such an
+// index behind, and it would make a retry of the statement fail.
+func dropInvalidIndexes(ctx context.Context, conn *pgxpool.Conn, m migration.Migration) error {
+	sql := `SELECT EXISTS (
+		SELECT 1 FROM pg_index WHERE indexrelid = to_regclass($1) AND NOT indisvalid
+	);`
+
+	for _, name := range concurrentIndexNames(m.UpSQL()) {
+		// Check whether the index exists and is INVALID.
+		var invalid bool
+		err := conn.QueryRow(ctx, sql, name).Scan(&invalid)
+		if err != nil {
+			return err
+		}
+		if !invalid {
+			continue
+		}
+
+		// Drop the INVALID index.
+		_, err = conn.Exec(ctx, "DROP INDEX CONCURRENTLY IF EXISTS "+name+";")
+		if err != nil {
+			return fmt.Errorf("could not drop INVALID index %s: %w", name, err)
+		}
+
+		emit(event{
+			Event:     "index_dropped",
+			Message:   fmt.Sprintf("Dropped INVALID index %s left behind by migration %s", name, m.Key()),
+			Migration: m.Key(),
+			Index:     name,
+		})
+	}
+
+	return nil
+}
+
+// execUpMigrationsInTx executes all "up" migrations contained in a
 // []migration.Migration in a single database transaction. If any migration
 // fails, then the transaction is rolled back and no migrations are committed.
-func execUpMigrations(ctx context.Context, pool *pgxpool.Pool, ms []migration.Migration) error {
+func execUpMigrationsInTx(ctx context.Context, conn *pgxpool.Conn, ms []migration.Migration) error {
+	if len(ms) == 0 {
+		return nil
+	}
+
 	// Begin a database transaction.
-	tx, err := pool.Begin(ctx)
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
 	defer tx.Rollback(ctx)
 
-	// sql := `SET search_path TO public;`
-	// _, err = tx.Exec(ctx, sql)
-	// if err != nil {
-	// 	return err
-	// }
+	// Execute each SQL hook in a savepoint, so that a failed hook that only
+	// warns does not abort the transaction.
+	execSQL := func(sql string) error {
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer sp.Rollback(ctx)
+
+		_, err = sp.Exec(ctx, sql)
+		if err != nil {
+			return err
+		}
+
+		return sp.Commit(ctx)
+	}
 
 	// Migrate schema.
+	durations := make([]time.Duration, 0, len(ms))
 	for _, m := range ms {
 		// Execute SQL statement from migration.
+		start := time.Now()
 		_, err = tx.Exec(ctx, m.UpSQL())
 		if err != nil {
 			// return err
-			return fmt.Errorf("could not execute migration %d_%s: %s", m.Version(), m.Name(), err)
+			return fmt.Errorf("could not execute migration %s: %w", m.Key(), err)
 		}
+		durations = append(durations, time.Since(start))
 
-		// Insert migration version into schema_version table
-		stmt := "INSERT INTO schema_versions (version, created_at) VALUES ($1, now());"
-		_, err = tx.Exec(ctx, stmt, m.Version())
+		// Record the executed migration.
+		sql, args := recordMigrationSQL(m)
+		_, err = tx.Exec(ctx, sql, args...)
+		if err != nil {
+			return err
+		}
+
+		// Run the after_each SQL hooks in the transaction. The command hooks
+		// run after the commit, so that they do not wait on the locks held
+		// by the transaction, or act on migrations that are rolled back.
+		err = runHooksIf(ctx, hookAfterEach, execSQL, migrationHookEnv(m), hook.isSQL)
 		if err != nil {
 			return err
 		}
@@ -148,6 +662,29 @@ func execUpMigrations(ctx context.Context, pool *pgxpool.Pool, ms []migration.Mi
 	if err != nil {
 		return err
 	}
+	for i, m := range ms {
+		emitMigrationApplied(m, durations[i])
+	}
+
+	// Run the after_each command hooks of the committed migrations.
+	for _, m := range ms {
+		err = runHooksIf(ctx, hookAfterEach, nil, migrationHookEnv(m), hook.isCommand)
+		if err != nil {
+			return err
+		}
+	}
 
 	return err
 }
+
+// emitMigrationApplied reports a migration that has been executed and
+// committed.
+func emitMigrationApplied(m migration.Migration, d time.Duration) {
+	emit(event{
+		Event:      "migration_applied",
+		Message:    fmt.Sprintf("Applied migration %s in %s.", m.Key(), d),
+		Migration:  m.Key(),
+		Version:    m.Version(),
+		DurationMS: durationMS(d),
+	})
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Unit test concurrentIndexRegexp
+func TestConcurrentIndexRegexp(t *testing.T) {
+	sql := `CREATE INDEX CONCURRENTLY cars_make_mnrk_idx ON cars (make);
+create unique index concurrently if not exists "Cars_VIN" ON cars (vin);
+CREATE INDEX cars_color_mnrk_idx ON cars (color);`
+
+	exp := []string{"cars_make_mnrk_idx", `"Cars_VIN"`}
+	act := make([]string, 0)
+	for _, match := range concurrentIndexRegexp.FindAllStringSubmatch(sql, -1) {
+		act = append(act, match[1])
+	}
+	if !reflect.DeepEqual(exp, act) {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test concurrentIndexNames()
+func TestConcurrentIndexNames(t *testing.T) {
+	sql := `CREATE INDEX CONCURRENTLY invoices_total_mnrk_idx ON billing.invoices (total);
+CREATE INDEX CONCURRENTLY cars_make_mnrk_idx ON ONLY cars (make);`
+
+	exp := []string{"billing.invoices_total_mnrk_idx", "cars_make_mnrk_idx"}
+	act := concurrentIndexNames(sql)
+	if !reflect.DeepEqual(exp, act) {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test schemaVersionsTable()
+func TestSchemaVersionsTable(t *testing.T) {
+	defer viper.Set("migrate.schema_versions_table", nil)
+
+	exp := `"schema_versions"`
+	act := schemaVersionsTable().Sanitize()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	viper.Set("migrate.schema_versions_table", "monarch.schema_versions")
+	exp = `"monarch"."schema_versions"`
+	act = schemaVersionsTable().Sanitize()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test changedMigrations()
+func TestChangedMigrations(t *testing.T) {
+	var versioned, unchanged, changed migration.Migration
+	versioned.SetName("create_table_users")
+	versioned.SetVersion(1)
+	unchanged.SetName("create_view_active_users")
+	unchanged.SetRepeatable(true)
+	unchanged.SetUpSQL("CREATE OR REPLACE VIEW active_users AS SELECT 1;")
+	changed.SetName("create_function_touch")
+	changed.SetRepeatable(true)
+	changed.SetUpSQL("CREATE OR REPLACE FUNCTION touch() ...;")
+
+	checksums := map[string]string{
+		unchanged.Name(): unchanged.Checksum(),
+		changed.Name():   "stale",
+	}
+	ms := changedMigrations([]migration.Migration{versioned, unchanged, changed}, checksums)
+
+	exp := []string{"1_create_table_users", "R_create_function_touch"}
+	if len(ms) != len(exp) {
+		t.Fatalf("want %d migrations; got %d", len(exp), len(ms))
+	}
+	for i, m := range ms {
+		if exp[i] != m.Key() {
+			t.Errorf("want %q; got %q", exp[i], m.Key())
+		}
+	}
+}
+
+// Unit test recordMigrationSQL()
+func TestRecordMigrationSQL(t *testing.T) {
+	var m migration.Migration
+	m.SetName("create_view_active_users")
+	m.SetVersion(7)
+
+	sql, args := recordMigrationSQL(m)
+	if exp := insertSchemaVersionSQL(); exp != sql {
+		t.Errorf("want %q; got %q", exp, sql)
+	}
+	if len(args) != 1 || args[0] != int64(7) {
+		t.Errorf("want [7]; got %v", args)
+	}
+
+	m.SetRepeatable(true)
+	sql, args = recordMigrationSQL(m)
+	if !strings.Contains(sql, `"schema_repeatable_migrations"`) {
+		t.Errorf("want an insert into schema_repeatable_migrations; got %q", sql)
+	}
+	if len(args) != 2 || args[0] != m.Name() || args[1] != m.Checksum() {
+		t.Errorf("want [%s %s]; got %v", m.Name(), m.Checksum(), args)
+	}
+}
+
+// Unit test migrationVars() and renderMigrations()
+func TestRenderMigrations(t *testing.T) {
+	viper.Set("staging.vars", map[string]interface{}{"reader_role": "app_reader", "tablespace": "fast"})
+	migrateVars = []string{"Tablespace=slow"}
+	defer func() {
+		viper.Set("staging.vars", nil)
+		migrateVars = nil
+	}()
+
+	vars, err := migrationVars("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := "slow", vars["tablespace"]; exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	var tmpl, plain migration.Migration
+	tmpl.SetTemplate(true)
+	tmpl.SetUpSQL("CREATE TABLE t () TABLESPACE {{.tablespace}};\nGRANT SELECT ON t TO {{.reader_role}};")
+	plain.SetUpSQL("SELECT '{{1,2},{3,4}}'::int[];")
+	ms := []migration.Migration{tmpl, plain}
+
+	err = renderMigrations(ms, vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp, act := "CREATE TABLE t () TABLESPACE slow;\nGRANT SELECT ON t TO app_reader;", ms[0].UpSQL(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+	if exp, act := plain.UpSQL(), ms[1].UpSQL(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	// An undefined variable is an error.
+	tmpl.SetUpSQL("GRANT SELECT ON t TO {{.writer_role}};")
+	err = renderMigrations([]migration.Migration{tmpl}, vars)
+	if err == nil {
+		t.Errorf("want error; got nil")
+	}
+
+	// A --var must be key=value.
+	migrateVars = []string{"=x"}
+	_, err = migrationVars("staging")
+	if err == nil {
+		t.Errorf("want error; got nil")
+	}
+}
+
+// Test that an after_each command hook runs after the migration is committed.
+func TestMigrateDBCommandHook(t *testing.T) {
+	if _, err := exec.LookPath("psql"); err != nil {
+		t.Skip("psql is not installed")
+	}
+
+	// Set up arguments.
+	cmd := &cobra.Command{}
+	args := make([]string, 0)
+
+	// Initialize configuration from config file.
+	initConfig()
+	dbForce = true // Do not ask for confirmation.
+	defer func() { dbForce = false }()
+
+	// Create a DB with the default name.
+	err := resetDB(cmd, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dropDB(cmd, args) // Do cleanup
+
+	// Create a migration.
+	mkdirMigrations(cmd, args)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	err = createMigration("CreateTableWidgets", "CREATE TABLE widgets (id bigint);", "DROP TABLE widgets;")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The hook connects to the database in a session of its own, so it sees
+	// the table only if the migration is committed.
+	var srv dbServer
+	srv.initFromConfigEnv(migrateEnv)
+	viper.Set("migrate.hooks", map[string]interface{}{
+		hookAfterEach: []map[string]interface{}{
+			{"command": "psql '" + srv.dsn() + "' -c 'SELECT 1 FROM widgets;'"},
+		},
+	})
+	defer viper.Set("migrate.hooks", nil)
+
+	err = migrateDB(cmd, args)
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/kevinsapp/monarch/pkg/fileutil"
@@ -11,6 +14,11 @@ import (
 
 const migrationsDir string = "migrations"
 
+// lastVersion is the version of the most recently created migration. It
+// ensures that migrations created by a single command have distinct,
+// increasing versions.
+var lastVersion int64
+
 func init() {
 	generateCmd.AddCommand(migrationCmd)
 	migrationCmd.AddCommand(addCmd)
@@ -54,12 +62,19 @@ var renameCmd = &cobra.Command{
 
 // createMigration creates a migration file based on arguments.
 func createMigration(name, upSQL, downSQL string) error {
+	return createMigrationTx(name, upSQL, downSQL, migration.TransactionDefault)
+}
+
+// createMigrationTx creates a migration file based on arguments that is
+// executed in the transaction mode specified by "transaction".
+func createMigrationTx(name, upSQL, downSQL, transaction string) error {
 	// Configure a migration object.
 	m := new(migration.Migration)
 	m.SetName(name)
 	m.SetUpSQL(upSQL)
 	m.SetDownSQL(downSQL)
-	m.SetVersion(time.Now().UnixNano())
+	m.SetVersion(nextVersion())
+	m.SetTransaction(transaction)
 
 	// Write migration file.
 	_, err := m.WriteToFile(migrationsDir)
@@ -76,6 +91,61 @@ func createMigration(name, upSQL, downSQL string) error {
 func mkdirMigrations(cmd *cobra.Command, args []string) {
 	err := fileutil.MkdirP(migrationsDir)
 	if err != nil {
-		fmt.Printf("Error creating directory %q: %s\n", migrationsDir, err)
+		emit(event{
+			Event:   "error",
+			Message: fmt.Sprintf("Error creating directory %q: %s", migrationsDir, err),
+			File:    migrationsDir,
+			Error:   err.Error(),
+		})
 	}
 }
+
+// nextVersion returns a version based on the current time that is later than
+// the version of any migration created before it by this process.
+func nextVersion() int64 {
+	v := time.Now().UnixNano()
+	if v <= lastVersion {
+		v = lastVersion + 1
+	}
+	lastVersion = v
+
+	return v
+}
+
+// findLatestMigration reads in the versioned migration with the latest version
+// whose name is "name" converted to snake_case. If there is no such migration,
+// findLatestMigration returns nil.
+func findLatestMigration(name string) (*migration.Migration, error) {
+	files, err := ioutil.ReadDir(migrationsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Files are sorted by name, and so by version; search from the end.
+	var probe migration.Migration
+	probe.SetName(name)
+	suffix := "_" + probe.Name() + ".sql"
+	for i := len(files) - 1; i >= 0; i-- {
+		if !strings.HasSuffix(files[i].Name(), suffix) {
+			continue
+		}
+
+		m := new(migration.Migration)
+		err = m.ReadFromFile(migrationsDir + "/" + files[i].Name())
+		if err != nil {
+			return nil, err
+		}
+
+		// Repeatable migrations, named R_<name>.sql, sort after the versioned
+		// ones but have no version; skip them.
+		if m.Repeatable() {
+			continue
+		}
+		return m, nil
+	}
+
+	return nil, nil
+}
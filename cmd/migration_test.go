@@ -84,3 +84,61 @@ func TestMkdirMigrations(t *testing.T) {
 		t.Errorf("want %s; got %s", exp, act)
 	}
 }
+
+// readMigrationsHelper reads in every migration file in the migrations
+// directory, in filename order, and fails the test if there are not exactly
+// "n" of them.
+func readMigrationsHelper(n int, t *testing.T) []migration.Migration {
+	// Get the list of files in the migrations directory.
+	files, err := ioutil.ReadDir(migrationsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Check that the expected number of files was created.
+	if l := len(files); l != n {
+		t.Fatalf("wrong number of files created: want %d; got %d", n, l)
+	}
+
+	// Verify that each file can be read in to a migration object.
+	ms := make([]migration.Migration, 0, n)
+	for _, f := range files {
+		path := fmt.Sprintf("%s/%s", migrationsDir, f.Name())
+		m := new(migration.Migration)
+		err = m.ReadFromFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ms = append(ms, *m)
+	}
+
+	return ms
+}
+
+// Unit test findLatestMigration() with a repeatable migration of the same name
+func TestFindLatestMigrationSkipsRepeatable(t *testing.T) {
+	var cmd = &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+
+	err := createMigration("CreateView_x", "CREATE VIEW x AS SELECT 1;", "DROP VIEW x;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := new(migration.Migration)
+	r.SetName("CreateView_x")
+	r.SetRepeatable(true)
+	r.SetUpSQL("CREATE OR REPLACE VIEW x AS SELECT 2;")
+	_, err = r.WriteToFile(migrationsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := findLatestMigration("CreateView_x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || m.Repeatable() || m.Version() == 0 {
+		t.Errorf("want the versioned migration; got %+v", m)
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/spf13/cobra"
+)
+
+// Output formats set by the --output flag.
+const (
+	// outputText prints a line of text for each event. It is the default.
+	outputText string = "text"
+
+	// outputJSON prints a JSON object on a line for each event.
+	outputJSON string = "json"
+)
+
+// Exit codes of monarch. Any error without a more specific code exits with
+// exitError.
+const (
+	exitOK                = 0
+	exitError             = 1
+	exitSQLError          = 2
+	exitPendingMigrations = 3
+	exitLockTimeout       = 4
+	exitChecksumMismatch  = 5
+)
+
+// lockNotAvailable is the SQLSTATE of an error raised when a lock cannot be
+// acquired within lock_timeout.
+const lockNotAvailable string = "55P03"
+
+// outputFormat is set by the --output flag.
+var outputFormat string
+
+// output is the writer of events.
+var output io.Writer = os.Stdout
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "output format: text or json")
+}
+
+// event is an action reported by a command. In text format only its message
+// is printed; in JSON format it is printed as an object without its empty
+// fields.
+type event struct {
+	Event      string  `json:"event"`
+	Message    string  `json:"message,omitempty"`
+	Database   string  `json:"database,omitempty"`
+	Migration  string  `json:"migration,omitempty"`
+	Version    int64   `json:"version,omitempty"`
+	Table      string  `json:"table,omitempty"`
+	Rows       int64   `json:"rows,omitempty"`
+	Count      int     `json:"count,omitempty"`
+	Seed       string  `json:"seed,omitempty"`
+	Hook       string  `json:"hook,omitempty"`
+	Extension  string  `json:"extension,omitempty"`
+	Index      string  `json:"index,omitempty"`
+	SQL        string  `json:"sql,omitempty"`
+	File       string  `json:"file,omitempty"`
+	Status     string  `json:"status,omitempty"`
+	DurationMS float64 `json:"duration_ms,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	SQLState   string  `json:"sqlstate,omitempty"`
+	ExitCode   int     `json:"exit_code,omitempty"`
+}
+
+// emit reports an event in the output format.
+func emit(e event) {
+	if outputFormat == outputJSON {
+		json.NewEncoder(output).Encode(e)
+		return
+	}
+	fmt.Fprintln(output, e.Message)
+}
+
+// durationMS converts a duration to milliseconds.
+func durationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// promptOutput returns the writer of questions asked by a command. In JSON
+// format they are written to standard error, so that standard output holds
+// only events.
+func promptOutput(cmd *cobra.Command) io.Writer {
+	if outputFormat == outputJSON {
+		return cmd.ErrOrStderr()
+	}
+	return cmd.OutOrStdout()
+}
+
+// commandOutput returns the writer of the standard output of an external
+// command run by monarch, such as a hook. In JSON format it is standard
+// error, so that standard output holds only events.
+func commandOutput() io.Writer {
+	if outputFormat == outputJSON {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// exitErr is an error with a specific exit code.
+type exitErr struct {
+	code int
+	err  error
+}
+
+// Error ...
+func (e *exitErr) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap ...
+func (e *exitErr) Unwrap() error {
+	return e.err
+}
+
+// withExitCode wraps err so that monarch exits with code.
+func withExitCode(code int, err error) error {
+	return &exitErr{code: code, err: err}
+}
+
+// exitCode returns the exit code for an error: the code it was wrapped with,
+// exitLockTimeout or exitSQLError for an error raised by PostgreSQL, or
+// exitError.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var ee *exitErr
+	if errors.As(err, &ee) {
+		return ee.code
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if pgErr.Code == lockNotAvailable {
+			return exitLockTimeout
+		}
+		return exitSQLError
+	}
+
+	return exitError
+}
+
+// fail reports an error and exits with its exit code.
+func fail(err error) {
+	code := exitCode(err)
+	if outputFormat == outputJSON {
+		e := event{Event: "error", Error: err.Error(), ExitCode: code}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			e.SQLState = pgErr.Code
+		}
+		emit(e)
+	} else {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+
+	os.Exit(code)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgconn"
+)
+
+// Unit test emit()
+func TestEmit(t *testing.T) {
+	var buf bytes.Buffer
+	output = &buf
+	defer func() {
+		outputFormat = outputText
+		output = os.Stdout
+	}()
+
+	e := event{Event: "database_created", Message: `Database "app" created.`, Database: "app", DurationMS: 1.5}
+
+	outputFormat = outputText
+	emit(e)
+	if exp, act := "Database \"app\" created.\n", buf.String(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	buf.Reset()
+	outputFormat = outputJSON
+	emit(e)
+	exp := `{"event":"database_created","message":"Database \"app\" created.","database":"app","duration_ms":1.5}` + "\n"
+	if act := buf.String(); exp != act {
+		t.Errorf("\nwant %s\n got %s", exp, act)
+	}
+}
+
+// Unit test exitCode()
+func TestExitCode(t *testing.T) {
+	sqlErr := &pgconn.PgError{Code: "42P01"}
+	lockErr := &pgconn.PgError{Code: lockNotAvailable}
+
+	cases := []struct {
+		err error
+		exp int
+	}{
+		{nil, exitOK},
+		{errors.New("requires a table argument"), exitError},
+		{fmt.Errorf("could not execute migration 1_x: %w", sqlErr), exitSQLError},
+		{fmt.Errorf("could not execute migration 1_x: %w", lockErr), exitLockTimeout},
+		{withExitCode(exitPendingMigrations, errors.New("pending")), exitPendingMigrations},
+		{fmt.Errorf("seed: %w", withExitCode(exitChecksumMismatch, errors.New("changed"))), exitChecksumMismatch},
+	}
+	for _, c := range cases {
+		if act := exitCode(c.err); c.exp != act {
+			t.Errorf("%v: want %d; got %d", c.err, c.exp, act)
+		}
+	}
+}
+
+// Unit test commandOutput()
+func TestCommandOutput(t *testing.T) {
+	defer func() { outputFormat = outputText }()
+
+	outputFormat = outputText
+	if act := commandOutput(); act != os.Stdout {
+		t.Errorf("want os.Stdout; got %v", act)
+	}
+
+	outputFormat = outputJSON
+	if act := commandOutput(); act != os.Stderr {
+		t.Errorf("want os.Stderr; got %v", act)
+	}
+}
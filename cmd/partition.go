@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Partition options set by command flags.
+var (
+	partitionName         string
+	partitionFrom         string
+	partitionTo           string
+	partitionValues       []string
+	partitionModulus      int
+	partitionRemainder    int
+	partitionDefault      bool
+	partitionConcurrently bool
+	partitionMonthly      int
+	partitionStart        string
+)
+
+func init() {
+	migrationCmd.AddCommand(attachCmd)
+	migrationCmd.AddCommand(detachCmd)
+	createCmd.AddCommand(createPartitionCmd)
+	createCmd.AddCommand(createPartitionsCmd)
+	attachCmd.AddCommand(attachPartitionCmd)
+	detachCmd.AddCommand(detachPartitionCmd)
+
+	createPartitionCmd.Flags().StringVar(&partitionName, "name", "", "name of the partition (default is generated from the table name and the bound)")
+	addPartitionBoundFlags(createPartitionCmd.Flags())
+	addPartitionBoundFlags(attachPartitionCmd.Flags())
+	addPartitionBoundFlags(detachPartitionCmd.Flags())
+	detachPartitionCmd.Flags().BoolVar(&partitionConcurrently, "concurrently", false, "detach without blocking queries on the table; requires PostgreSQL 14")
+	createPartitionsCmd.Flags().IntVar(&partitionMonthly, "monthly", 0, "number of monthly range partitions to create")
+	createPartitionsCmd.Flags().StringVar(&partitionStart, "start", "", "first month to partition, as YYYY-MM (default is the current month)")
+}
+
+// addPartitionBoundFlags defines the flags that set a partition bound.
+func addPartitionBoundFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&partitionFrom, "from", "", "inclusive lower bound of a range partition, or MINVALUE")
+	fs.StringVar(&partitionTo, "to", "", "exclusive upper bound of a range partition, or MAXVALUE")
+	fs.StringSliceVar(&partitionValues, "values", nil, "comma-separated values of a list partition")
+	fs.IntVar(&partitionModulus, "modulus", 0, "modulus of a hash partition")
+	fs.IntVar(&partitionRemainder, "remainder", 0, "remainder of a hash partition")
+	fs.BoolVar(&partitionDefault, "default", false, "make the partition the default partition")
+}
+
+// attachCmd ...
+var attachCmd = &cobra.Command{
+	Use: "attach",
+}
+
+// detachCmd ...
+var detachCmd = &cobra.Command{
+	Use: "detach",
+}
+
+// createPartitionCmd generates a migration file to create a partition of a
+// partitioned table.
+var createPartitionCmd = &cobra.Command{
+	Use:   "partition [table]",
+	Short: "Generate a migration file to create a partition of the partitioned table [table].",
+	Long: `Generate a migration file to create a partition of the partitioned table [table]. The
+	bound of the partition is given by --from and --to for a range partition, --values for a
+	list partition, --modulus and --remainder for a hash partition, or --default.`,
+	RunE: createPartitionMigration,
+}
+
+// createPartitionsCmd generates a migration file to create several range
+// partitions of a partitioned table.
+var createPartitionsCmd = &cobra.Command{
+	Use:   "partitions [table]",
+	Short: "Generate a migration file to create monthly partitions of the partitioned table [table].",
+	Long: `Generate a migration file to create --monthly consecutive monthly range partitions of the
+	partitioned table [table], starting with the --start month. Each partition is named after
+	the table and its month, e.g. events_2024_01.`,
+	RunE: createPartitionsMigration,
+}
+
+// attachPartitionCmd generates a migration file to attach a table as a
+// partition of a partitioned table.
+var attachPartitionCmd = &cobra.Command{
+	Use:   "partition [table] [partition]",
+	Short: "Generate a migration file to attach [partition] as a partition of [table].",
+	RunE:  attachPartitionMigration,
+}
+
+// detachPartitionCmd generates a migration file to detach a partition of a
+// partitioned table.
+var detachPartitionCmd = &cobra.Command{
+	Use:   "partition [table] [partition]",
+	Short: "Generate a migration file to detach [partition] from [table].",
+	Long: `Generate a migration file to detach [partition] from [table]. The detached partition
+	remains a table. The "down" migration reattaches the partition if its bound is given.
+
+	With --concurrently, the migration is executed outside of a transaction.`,
+	RunE: detachPartitionMigration,
+}
+
+// createPartitionMigration creates a migration file to create a partition.
+func createPartitionMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a table name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a table argument")
+	}
+
+	// Set partition data.
+	p, err := partitionFromFlags(args[0])
+	if err != nil {
+		return err
+	}
+	if partitionName != "" {
+		p.SetName(partitionName)
+	}
+	ps := []*sqlt.Partition{p}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(ps, sqlt.CreatePartitionsTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(ps, sqlt.DropPartitionsTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("CreatePartition_"+p.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// createPartitionsMigration creates a migration file to create consecutive
+// monthly range partitions.
+func createPartitionsMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a table name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a table argument")
+	}
+	if partitionMonthly < 1 {
+		return errors.New("requires --monthly with a number of partitions")
+	}
+
+	// Find the first day of the start month.
+	start := time.Now().UTC()
+	if partitionStart != "" {
+		var err error
+		start, err = time.Parse("2006-01", partitionStart)
+		if err != nil {
+			return fmt.Errorf("invalid --start %q: want YYYY-MM", partitionStart)
+		}
+	}
+	start = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	// Set partition data, in order of creation and in reverse order.
+	ps := make([]*sqlt.Partition, 0, partitionMonthly)
+	rs := make([]*sqlt.Partition, partitionMonthly)
+	for i := 0; i < partitionMonthly; i++ {
+		from := start.AddDate(0, i, 0)
+		p := new(sqlt.Partition)
+		p.SetParent(args[0])
+		p.SetName(p.Parent() + from.Format("_2006_01"))
+		p.SetRange(from.Format("2006-01-02"), from.AddDate(0, 1, 0).Format("2006-01-02"))
+		ps = append(ps, p)
+		rs[partitionMonthly-1-i] = p
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(ps, sqlt.CreatePartitionsTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(rs, sqlt.DropPartitionsTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("CreateMonthlyPartitions_"+ps[0].Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// attachPartitionMigration creates a migration file to attach a partition.
+func attachPartitionMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a table name and a partition name.
+	if len(args) < 2 {
+		return errors.New("requires two arguments: table and partition")
+	}
+
+	// Set partition data.
+	p, err := partitionFromFlags(args[0])
+	if err != nil {
+		return err
+	}
+	p.SetName(args[1])
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(p, sqlt.AttachPartitionTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(p, sqlt.DetachPartitionTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("AttachPartition_"+p.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// detachPartitionMigration creates a migration file to detach a partition.
+func detachPartitionMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a table name and a partition name.
+	if len(args) < 2 {
+		return errors.New("requires two arguments: table and partition")
+	}
+
+	// Set partition data. A bound is optional; without one, the partition
+	// cannot be reattached by the "down" migration.
+	p, err := partitionFromFlags(args[0])
+	hasBound := err == nil
+	if err != nil && err != errNoPartitionBound {
+		return err
+	}
+	p.SetName(args[1])
+	p.SetConcurrently(partitionConcurrently)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(p, sqlt.DetachPartitionTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	var downSQL string
+	if hasBound {
+		downSQL, err = sqlt.ProcessTmpl(p, sqlt.AttachPartitionTmpl)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Create migration file.
+	transaction := migration.TransactionDefault
+	if p.Concurrently() {
+		transaction = migration.TransactionNone
+	}
+	err = createMigrationTx("DetachPartition_"+p.Name(), upSQL, downSQL, transaction)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// errNoPartitionBound is returned by partitionFromFlags when no bound flag is
+// set.
+var errNoPartitionBound = errors.New("requires a partition bound: --from and --to, --values, --modulus and --remainder, or --default")
+
+// partitionFromFlags configures a partition of the table parent with the
+// bound set by command flags. The partition is returned even if the flags do
+// not set a bound, together with errNoPartitionBound.
+func partitionFromFlags(parent string) (*sqlt.Partition, error) {
+	p := new(sqlt.Partition)
+	p.SetParent(parent)
+
+	bounds := 0
+	if partitionFrom != "" || partitionTo != "" {
+		if partitionFrom == "" || partitionTo == "" {
+			return nil, errors.New("a range partition requires both --from and --to")
+		}
+		p.SetRange(partitionFrom, partitionTo)
+		bounds++
+	}
+	if len(partitionValues) > 0 {
+		for _, v := range partitionValues {
+			p.AddValue(v)
+		}
+		bounds++
+	}
+	if partitionModulus > 0 {
+		if partitionRemainder < 0 || partitionRemainder >= partitionModulus {
+			return nil, errors.New("--remainder must be at least 0 and less than --modulus")
+		}
+		p.SetHash(partitionModulus, partitionRemainder)
+		bounds++
+	}
+	if partitionDefault {
+		p.SetDefault(true)
+		bounds++
+	}
+
+	switch bounds {
+	case 0:
+		return p, errNoPartitionBound
+	case 1:
+		return p, nil
+	}
+	return nil, errors.New("requires exactly one partition bound")
+}
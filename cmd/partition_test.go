@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/spf13/cobra"
+)
+
+// Unit test createPartitionMigration()
+func TestCreatePartitionMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetPartitionFlagsHelper()
+
+	partitionValues = []string{"EU", "UK"}
+	err := createPartitionMigration(cmd, []string{"orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(1, t)
+
+	expUp := `CREATE TABLE orders_eu_uk PARTITION OF orders
+	FOR VALUES IN ('EU', 'UK');`
+	expDown := `DROP TABLE IF EXISTS orders_eu_uk;`
+	if exp, act := expUp, ms[0].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+	if exp, act := expDown, ms[0].DownSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+
+	// A partition requires exactly one bound.
+	resetPartitionFlagsHelper()
+	err = createPartitionMigration(cmd, []string{"orders"})
+	if err != errNoPartitionBound {
+		t.Errorf("want %v; got %v", errNoPartitionBound, err)
+	}
+	partitionFrom, partitionTo, partitionDefault = "a", "m", true
+	err = createPartitionMigration(cmd, []string{"orders"})
+	if err == nil {
+		t.Error("want error for two bounds; got nil")
+	}
+}
+
+// Unit test createPartitionsMigration()
+func TestCreatePartitionsMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetPartitionFlagsHelper()
+
+	partitionMonthly = 3
+	partitionStart = "2024-11"
+	err := createPartitionsMigration(cmd, []string{"events"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(1, t)
+
+	expUp := `CREATE TABLE events_2024_11 PARTITION OF events
+	FOR VALUES FROM ('2024-11-01') TO ('2024-12-01');
+
+CREATE TABLE events_2024_12 PARTITION OF events
+	FOR VALUES FROM ('2024-12-01') TO ('2025-01-01');
+
+CREATE TABLE events_2025_01 PARTITION OF events
+	FOR VALUES FROM ('2025-01-01') TO ('2025-02-01');`
+	expDown := `DROP TABLE IF EXISTS events_2025_01;
+DROP TABLE IF EXISTS events_2024_12;
+DROP TABLE IF EXISTS events_2024_11;`
+	if exp, act := expUp, ms[0].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+	if exp, act := expDown, ms[0].DownSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+}
+
+// Unit test attachPartitionMigration() and detachPartitionMigration()
+func TestAttachAndDetachPartitionMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetPartitionFlagsHelper()
+
+	partitionModulus, partitionRemainder = 4, 0
+	err := attachPartitionMigration(cmd, []string{"users", "users_0_of_4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resetPartitionFlagsHelper()
+	partitionConcurrently = true
+	err = detachPartitionMigration(cmd, []string{"users", "users_0_of_4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(2, t)
+
+	attachSQL := `ALTER TABLE users
+	ATTACH PARTITION users_0_of_4 FOR VALUES WITH (MODULUS 4, REMAINDER 0);`
+	detachSQL := `ALTER TABLE users
+	DETACH PARTITION users_0_of_4;`
+	cases := [][]string{
+		{attachSQL, ms[0].UpSQL()},
+		{detachSQL, ms[0].DownSQL()},
+		{"ALTER TABLE users\n\tDETACH PARTITION users_0_of_4 CONCURRENTLY;", ms[1].UpSQL()},
+		{"", ms[1].DownSQL()},
+		{migration.TransactionNone, ms[1].Transaction()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+}
+
+// resetPartitionFlagsHelper resets the variables bound to partition flags.
+func resetPartitionFlagsHelper() {
+	partitionName, partitionFrom, partitionTo = "", "", ""
+	partitionValues = nil
+	partitionModulus, partitionRemainder = 0, 0
+	partitionDefault, partitionConcurrently = false, false
+	partitionMonthly, partitionStart = 0, ""
+}
@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Policy and row-level security options set by command flags.
+var (
+	policyFor         string
+	policyTo          []string
+	policyUsing       string
+	policyWithCheck   string
+	policyRestrictive bool
+	rlsForce          bool
+)
+
+func init() {
+	migrationCmd.AddCommand(enableCmd)
+	migrationCmd.AddCommand(disableCmd)
+	createCmd.AddCommand(createPolicyCmd)
+	dropCmd.AddCommand(dropPolicyCmd)
+	enableCmd.AddCommand(enableRowLevelSecurityCmd)
+	disableCmd.AddCommand(disableRowLevelSecurityCmd)
+
+	addPolicyFlags(createPolicyCmd.Flags())
+	addPolicyFlags(dropPolicyCmd.Flags())
+	enableRowLevelSecurityCmd.Flags().BoolVar(&rlsForce, "force", false, "apply row-level security to the table owner too")
+	disableRowLevelSecurityCmd.Flags().BoolVar(&rlsForce, "force", false, "row-level security was forced on the table owner")
+}
+
+// addPolicyFlags defines the flags shared by the policy commands.
+func addPolicyFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&policyFor, "for", "all", "command that the policy applies to: all, select, insert, update or delete")
+	fs.StringSliceVar(&policyTo, "to", nil, "comma-separated roles that the policy applies to (default is PUBLIC)")
+	fs.StringVar(&policyUsing, "using", "", "expression that existing rows must satisfy to be visible")
+	fs.StringVar(&policyWithCheck, "with-check", "", "expression that inserted or updated rows must satisfy")
+	fs.BoolVar(&policyRestrictive, "restrictive", false, "combine the policy with other policies using AND instead of OR")
+}
+
+// enableCmd ...
+var enableCmd = &cobra.Command{
+	Use: "enable",
+}
+
+// disableCmd ...
+var disableCmd = &cobra.Command{
+	Use: "disable",
+}
+
+// createPolicyCmd generates a migration file to create a row-level security
+// policy on a table.
+var createPolicyCmd = &cobra.Command{
+	Use:   "policy [tablename] [name]",
+	Short: "Generate a migration file to create a row-level security policy on a table.",
+	Long: `Generate a migration file to create a row-level security policy named [name] on a table.
+	Policies have no effect until row-level security is enabled on the table with "enable
+	row-level-security". For example:
+
+	  monarch g m create policy invoices tenant_isolation --using "tenant_id = current_setting('app.tenant_id')::bigint"`,
+	RunE: createPolicyMigration,
+}
+
+// dropPolicyCmd generates a migration file to drop a row-level security policy
+// from a table.
+var dropPolicyCmd = &cobra.Command{
+	Use:   "policy [tablename] [name]",
+	Short: "Generate a migration file to drop a row-level security policy from a table.",
+	Long: `Generate a migration file to drop a row-level security policy from a table. The flags
+	should match those used to create the policy so that the "down" migration can restore it.`,
+	RunE: dropPolicyMigration,
+}
+
+// enableRowLevelSecurityCmd generates a migration file to enable row-level
+// security on a table.
+var enableRowLevelSecurityCmd = &cobra.Command{
+	Use:   "row-level-security [tablename]",
+	Short: "Generate a migration file to enable row-level security on a table.",
+	Long: `Generate a migration file to enable row-level security on a table. Rows are then only
+	visible through the policies on the table; with no policies, no rows are visible. The table
+	owner bypasses the policies unless --force is given.`,
+	RunE: enableRowLevelSecurityMigration,
+}
+
+// disableRowLevelSecurityCmd generates a migration file to disable row-level
+// security on a table.
+var disableRowLevelSecurityCmd = &cobra.Command{
+	Use:   "row-level-security [tablename]",
+	Short: "Generate a migration file to disable row-level security on a table.",
+	RunE:  disableRowLevelSecurityMigration,
+}
+
+// createPolicyMigration creates a migration file to create a policy.
+func createPolicyMigration(cmd *cobra.Command, args []string) error {
+	// Set policy data.
+	p, err := policyFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(p, sqlt.CreatePolicyTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(p, sqlt.DropPolicyTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("CreatePolicyOn_"+p.TableName()+"_"+p.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// dropPolicyMigration creates a migration file to drop a policy.
+func dropPolicyMigration(cmd *cobra.Command, args []string) error {
+	// Set policy data.
+	p, err := policyFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(p, sqlt.DropPolicyTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(p, sqlt.CreatePolicyTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("DropPolicyOn_"+p.TableName()+"_"+p.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// enableRowLevelSecurityMigration creates a migration file to enable
+// row-level security on a table.
+func enableRowLevelSecurityMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a table name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a tablename argument")
+	}
+
+	// Set policy data. Only the table and the force option are used.
+	p := new(sqlt.Policy)
+	p.SetTableName(args[0])
+	p.SetForce(rlsForce)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(p, sqlt.EnableRowLevelSecurityTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(p, sqlt.DisableRowLevelSecurityTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("EnableRowLevelSecurityOn_"+p.TableName(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// disableRowLevelSecurityMigration creates a migration file to disable
+// row-level security on a table.
+func disableRowLevelSecurityMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a table name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a tablename argument")
+	}
+
+	// Set policy data. Only the table and the force option are used.
+	p := new(sqlt.Policy)
+	p.SetTableName(args[0])
+	p.SetForce(rlsForce)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(p, sqlt.DisableRowLevelSecurityTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(p, sqlt.EnableRowLevelSecurityTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("DisableRowLevelSecurityOn_"+p.TableName(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// policyFromArgs configures a policy from command arguments and flags.
+func policyFromArgs(args []string) (*sqlt.Policy, error) {
+	// Caller should supply a table name and a policy name.
+	if len(args) < 2 {
+		return nil, errors.New("requires two arguments: tablename and name")
+	}
+
+	p := new(sqlt.Policy)
+	p.SetTableName(args[0])
+	p.SetName(args[1])
+	p.SetCommand(policyFor)
+	switch p.Command() {
+	case "", "SELECT", "INSERT", "UPDATE", "DELETE":
+	default:
+		return nil, errors.New("--for must be all, select, insert, update or delete")
+	}
+	for _, v := range policyTo {
+		p.AddRole(v)
+	}
+	p.SetUsing(policyUsing)
+	p.SetWithCheck(policyWithCheck)
+	p.SetRestrictive(policyRestrictive)
+
+	// PostgreSQL rejects expressions that cannot apply to the command.
+	if p.Command() == "INSERT" && p.Using() != "" {
+		return nil, errors.New("a policy for insert cannot have --using")
+	}
+	if (p.Command() == "SELECT" || p.Command() == "DELETE") && p.WithCheck() != "" {
+		return nil, errors.New("a policy for select or delete cannot have --with-check")
+	}
+
+	return p, nil
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// Unit test createPolicyMigration() and dropPolicyMigration()
+func TestCreateAndDropPolicyMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetPolicyFlagsHelper()
+
+	policyFor = "update"
+	policyTo = []string{"appUser"}
+	policyUsing = "tenant_id = current_setting('app.tenant_id')::bigint"
+	policyWithCheck = "tenant_id = current_setting('app.tenant_id')::bigint"
+	err := createPolicyMigration(cmd, []string{"invoices", "tenantIsolation"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dropPolicyMigration(cmd, []string{"invoices", "tenantIsolation"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(2, t)
+
+	createSQL := `CREATE POLICY tenant_isolation ON invoices
+	FOR UPDATE
+	TO app_user
+	USING (tenant_id = current_setting('app.tenant_id')::bigint)
+	WITH CHECK (tenant_id = current_setting('app.tenant_id')::bigint);`
+	dropSQL := "DROP POLICY IF EXISTS tenant_isolation ON invoices;"
+	cases := [][]string{
+		{createSQL, ms[0].UpSQL()},
+		{dropSQL, ms[0].DownSQL()},
+		{dropSQL, ms[1].UpSQL()},
+		{createSQL, ms[1].DownSQL()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+
+	// PostgreSQL rejects USING on an INSERT policy.
+	policyFor = "insert"
+	err = createPolicyMigration(cmd, []string{"invoices", "tenantIsolation"})
+	if err == nil {
+		t.Error("want error for an insert policy with --using; got nil")
+	}
+}
+
+// Unit test enableRowLevelSecurityMigration()
+func TestEnableRowLevelSecurityMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetPolicyFlagsHelper()
+
+	rlsForce = true
+	err := enableRowLevelSecurityMigration(cmd, []string{"invoices"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(1, t)
+
+	expUp := `ALTER TABLE invoices ENABLE ROW LEVEL SECURITY;
+ALTER TABLE invoices FORCE ROW LEVEL SECURITY;`
+	expDown := `ALTER TABLE invoices NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE invoices DISABLE ROW LEVEL SECURITY;`
+	if exp, act := expUp, ms[0].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+	if exp, act := expDown, ms[0].DownSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+}
+
+// resetPolicyFlagsHelper resets the variables bound to policy flags.
+func resetPolicyFlagsHelper() {
+	policyFor, policyUsing, policyWithCheck = "", "", ""
+	policyTo = nil
+	policyRestrictive, rlsForce = false, false
+}
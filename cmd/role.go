@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Role options set by command flags.
+var (
+	roleLogin   bool
+	roleInRoles []string
+)
+
+func init() {
+	createCmd.AddCommand(createRoleCmd)
+	dropCmd.AddCommand(dropRoleCmd)
+
+	addRoleFlags(createRoleCmd.Flags())
+	addRoleFlags(dropRoleCmd.Flags())
+}
+
+// addRoleFlags defines the flags shared by the role commands.
+func addRoleFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&roleLogin, "login", false, "allow the role to log in")
+	fs.StringSliceVar(&roleInRoles, "in-role", nil, "comma-separated roles that the role is a member of")
+}
+
+// createRoleCmd generates a migration file to create a role.
+var createRoleCmd = &cobra.Command{
+	Use:   "role [name]",
+	Short: "Generate a migration file to create a role named [name].",
+	Long: `Generate a migration file to create a role named [name]. A role with --login is a user.
+	Passwords are not written to migration files; set them outside of version control, e.g.
+	with \password in psql.`,
+	RunE: createRoleMigration,
+}
+
+// dropRoleCmd generates a migration file to drop a role.
+var dropRoleCmd = &cobra.Command{
+	Use:   "role [name]",
+	Short: "Generate a migration file to drop a role named [name].",
+	Long: `Generate a migration file to drop a role named [name]. The flags should match those used
+	to create the role so that the "down" migration can restore it. A role cannot be dropped
+	while it owns objects or holds privileges; revoke them first.`,
+	RunE: dropRoleMigration,
+}
+
+// createRoleMigration creates a migration file to create a role.
+func createRoleMigration(cmd *cobra.Command, args []string) error {
+	// Set role data.
+	r, err := roleFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(r, sqlt.CreateRoleTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(r, sqlt.DropRoleTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("CreateRole_"+r.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// dropRoleMigration creates a migration file to drop a role.
+func dropRoleMigration(cmd *cobra.Command, args []string) error {
+	// Set role data.
+	r, err := roleFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(r, sqlt.DropRoleTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(r, sqlt.CreateRoleTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("DropRole_"+r.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// roleFromArgs configures a role from command arguments and flags.
+func roleFromArgs(args []string) (*sqlt.Role, error) {
+	// Caller should supply a role name as the first argument.
+	if len(args) < 1 {
+		return nil, errors.New("requires a name argument")
+	}
+
+	r := new(sqlt.Role)
+	r.SetName(args[0])
+	r.SetLogin(roleLogin)
+	for _, v := range roleInRoles {
+		r.AddInRole(v)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// Unit test createRoleMigration() and dropRoleMigration()
+func TestCreateAndDropRoleMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer func() { roleLogin, roleInRoles = false, nil }()
+
+	roleLogin = true
+	roleInRoles = []string{"readers", "writers"}
+	err := createRoleMigration(cmd, []string{"appUser"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dropRoleMigration(cmd, []string{"appUser"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(2, t)
+
+	createSQL := "CREATE ROLE app_user LOGIN IN ROLE readers, writers;"
+	dropSQL := "DROP ROLE IF EXISTS app_user;"
+	cases := [][]string{
+		{createSQL, ms[0].UpSQL()},
+		{dropSQL, ms[0].DownSQL()},
+		{dropSQL, ms[1].UpSQL()},
+		{createSQL, ms[1].DownSQL()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+}
@@ -3,11 +3,11 @@ package cmd
 
 import (
 	"errors"
-	"log"
-	"os"
+	"fmt"
 	"path/filepath"
 	"runtime"
 
+	"github.com/kevinsapp/monarch/pkg/sqlt"
 	"github.com/spf13/cobra"
 
 	"github.com/spf13/viper"
@@ -21,7 +21,12 @@ var cfgFile string
 var rootCmd = &cobra.Command{
 	Use:   "monarch",
 	Short: "Monarch is a tool for migrating SQL databases.",
-	Long:  `Monarch is a tool for migrating SQL databases.`,
+	Long: `Monarch is a tool for migrating SQL databases.
+
+	With --output json, each action is reported as a JSON object on a line of standard output.
+	Monarch exits with 0 on success, 1 on an error, 2 on an SQL error, 3 if migrations are pending
+	(db migrate --check), 4 on a lock timeout, and 5 on a checksum mismatch.`,
+	SilenceErrors: true,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	//	Run: func(cmd *cobra.Command, args []string) { },
@@ -31,7 +36,7 @@ var rootCmd = &cobra.Command{
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		fail(err)
 	}
 }
 
@@ -51,6 +56,12 @@ func init() {
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
+	if outputFormat != outputText && outputFormat != outputJSON {
+		bad := outputFormat
+		outputFormat = outputText
+		fail(fmt.Errorf("invalid --output %q: want %q or %q", bad, outputText, outputJSON))
+	}
+
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
@@ -58,7 +69,7 @@ func initConfig() {
 		// Determine project root directory.
 		dir, err := rootDir()
 		if err != nil {
-			log.Fatalf("ERROR: initConfig: %s\n", err)
+			fail(err)
 		}
 
 		// Search for config file in the project root directory with name "database" (without extension).
@@ -68,10 +79,16 @@ func initConfig() {
 
 	viper.AutomaticEnv() // read in environment variables that match
 
-	// If no config files is found, log an error.
+	// If no config files is found, fail.
 	err := viper.ReadInConfig()
 	if err != nil {
-		log.Fatalf("ERROR: initConfig: could not read in config file %q (with extenstion .json, .toml, or .yaml)", cfgFileBaseName)
+		fail(fmt.Errorf("could not read in config file %q (with extenstion .json, .toml, or .yaml)", cfgFileBaseName))
+	}
+
+	// Override SQL templates with the template files of the project.
+	err = sqlt.LoadTemplates(templatesDir)
+	if err != nil {
+		fail(err)
 	}
 }
 
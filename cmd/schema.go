@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+)
+
+// Schema options set by command flags.
+var (
+	schemaOwner   string
+	schemaCascade bool
+)
+
+func init() {
+	createCmd.AddCommand(createSchemaCmd)
+	dropCmd.AddCommand(dropSchemaCmd)
+
+	createSchemaCmd.Flags().StringVar(&schemaOwner, "owner", "", "role that owns the schema (default is the current user)")
+	dropSchemaCmd.Flags().StringVar(&schemaOwner, "owner", "", "role that owns the schema, used by the \"down\" migration")
+	dropSchemaCmd.Flags().BoolVar(&schemaCascade, "cascade", false, "also drop the objects in the schema")
+}
+
+// createSchemaCmd generates a migration file to create a schema.
+var createSchemaCmd = &cobra.Command{
+	Use:   "schema [name]",
+	Short: "Generate a migration file to create a schema named [name].",
+	Long: `Generate a migration file to create a schema named [name]. Objects in the schema are
+	created by qualifying their names with the schema name, for example:
+
+	  monarch g m create schema billing
+	  monarch g m create table billing.invoices total:numeric`,
+	RunE: createSchemaMigration,
+}
+
+// dropSchemaCmd generates a migration file to drop a schema.
+var dropSchemaCmd = &cobra.Command{
+	Use:   "schema [name]",
+	Short: "Generate a migration file to drop a schema named [name].",
+	Long: `Generate a migration file to drop a schema named [name]. The schema must be empty unless
+	--cascade is given.
+	WARNING: With --cascade, the "down" migration restores only the empty schema; the objects
+	in it and any data in them will be lost.`,
+	RunE: dropSchemaMigration,
+}
+
+// createSchemaMigration creates a migration file to create a schema.
+func createSchemaMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a schema name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a name argument")
+	}
+
+	// Set schema data.
+	s := new(sqlt.Schema)
+	s.SetName(args[0])
+	s.SetOwner(schemaOwner)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(s, sqlt.CreateSchemaTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(s, sqlt.DropSchemaTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("CreateSchema_"+s.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// dropSchemaMigration creates a migration file to drop a schema.
+func dropSchemaMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a schema name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a name argument")
+	}
+
+	// Set schema data.
+	s := new(sqlt.Schema)
+	s.SetName(args[0])
+	s.SetOwner(schemaOwner)
+	s.SetCascade(schemaCascade)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(s, sqlt.DropSchemaTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(s, sqlt.CreateSchemaTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("DropSchema_"+s.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
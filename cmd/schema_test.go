@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// Unit test createSchemaMigration() and dropSchemaMigration()
+func TestCreateAndDropSchemaMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer func() { schemaOwner, schemaCascade = "", false }()
+
+	schemaOwner = "billing_admin"
+	err := createSchemaMigration(cmd, []string{"Billing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemaCascade = true
+	err = dropSchemaMigration(cmd, []string{"Billing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(2, t)
+
+	createSQL := "CREATE SCHEMA IF NOT EXISTS billing AUTHORIZATION billing_admin;"
+	cases := [][]string{
+		{createSQL, ms[0].UpSQL()},
+		{"DROP SCHEMA IF EXISTS billing;", ms[0].DownSQL()},
+		{"DROP SCHEMA IF EXISTS billing CASCADE;", ms[1].UpSQL()},
+		{createSQL, ms[1].DownSQL()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+}
+
+// Unit test generators with schema-qualified names.
+func TestSchemaQualifiedMigrations(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+
+	err := createTableMigration(cmd, []string{"Billing.InvoiceItems", "unitPrice:numeric"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = renameTableMigration(cmd, []string{"billing.invoiceItems", "lineItems"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = createIndexMigration(cmd, []string{"billing.lineItems", "unitPrice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = addForeignKeyMigration(cmd, []string{"billing.lineItems", "billing.invoices"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(4, t)
+
+	// Verify the migration names.
+	names := []string{
+		"create_table_billing_invoice_items",
+		"rename_table_billing_invoice_items",
+		"create_index_on_billing_line_items_unit_price",
+		"add_foreign_key_to_billing_line_items",
+	}
+	for i, exp := range names {
+		if act := ms[i].Name(); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+	}
+
+	cases := [][]string{
+		{"ALTER TABLE billing.invoice_items RENAME TO line_items;", ms[1].UpSQL()},
+		{"ALTER TABLE billing.line_items RENAME TO invoice_items;", ms[1].DownSQL()},
+		{"CREATE INDEX line_items_unit_price_mnrk_idx ON billing.line_items (unit_price);", ms[2].UpSQL()},
+		{"DROP INDEX IF EXISTS billing.line_items_unit_price_mnrk_idx;", ms[2].DownSQL()},
+		{`ALTER TABLE billing.line_items
+	ADD COLUMN invoices_id bigint;
+
+ALTER TABLE billing.line_items
+	ADD CONSTRAINT line_items_invoices_mnrk_fkc FOREIGN KEY (invoices_id)
+	REFERENCES billing.invoices (id);`, ms[3].UpSQL()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+}
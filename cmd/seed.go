@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+	"github.com/kevinsapp/monarch/pkg/seed"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// seedsDir is the directory, in the current working directory, of the seed
+// files.
+const seedsDir string = "seeds"
+
+// defaultSchemaSeedsTable is the name of the table that records executed
+// seeds, unless "seed.schema_seeds_table" is set in the config file.
+const defaultSchemaSeedsTable = "schema_seeds"
+
+// Seed options set by command flags.
+var (
+	seedEnv        string
+	seedRepeatable bool
+)
+
+func init() {
+	dbCmd.AddCommand(seedDBCmd)
+	generateCmd.AddCommand(generateSeedCmd)
+
+	seedDBCmd.Flags().StringVar(&seedEnv, "env", "development", "config file section of the database to seed")
+	generateSeedCmd.Flags().BoolVar(&seedRepeatable, "repeatable", false, "generate a seed that is executed again whenever it changes")
+}
+
+// seedDBCmd ...
+var seedDBCmd = &cobra.Command{
+	Use:   "seed",
+	Short: `Seed a database with the seed files in the "` + seedsDir + `" directory.`,
+	Long: `Seed a database by executing the seed files in the "` + seedsDir + `" directory in a single
+	transaction. A seed file named <version>_<name>.sql is executed once, in order of version. A
+	seed file named R_<name>.sql is repeatable: it is executed after the versioned seeds, in order
+	of name, and executed again whenever its content changes, so it should be idempotent. Executed
+	seeds and their checksums are recorded in the ` + defaultSchemaSeedsTable + ` table. Seeding fails
+	if a versioned seed has changed since it was executed.`,
+	RunE: seedDB,
+}
+
+// generateSeedCmd ...
+var generateSeedCmd = &cobra.Command{
+	Use:   "seed [name]",
+	Short: `Generate a seed file in the "` + seedsDir + `" directory.`,
+	Long: `Generate a seed file named <version>_[name].sql in the "` + seedsDir + `" directory, or with
+	--repeatable, a seed file named R_[name].sql.`,
+	RunE: generateSeed,
+}
+
+// generateSeed creates a seed file.
+func generateSeed(cmd *cobra.Command, args []string) error {
+	// Caller should supply a seed name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a seed name argument")
+	}
+
+	// Configure a seed object.
+	s := new(seed.Seed)
+	s.SetName(args[0])
+	s.SetRepeatable(seedRepeatable)
+	if seedRepeatable {
+		s.SetSQL("-- Repeatable seed: executed again whenever this file changes, so it should be idempotent,\n-- e.g. INSERT ... ON CONFLICT DO UPDATE.\n")
+	} else {
+		s.SetVersion(nextVersion())
+		s.SetSQL("-- Seed: executed once.\n")
+	}
+
+	// Write seed file.
+	err := fileutil.MkdirP(seedsDir)
+	if err != nil {
+		return err
+	}
+	fn, err := s.WriteToFile(seedsDir)
+	if err != nil {
+		return err
+	}
+
+	emit(event{
+		Event:   "seed_created",
+		Message: fmt.Sprintf("Created seed file %s", fn),
+		Seed:    s.Key(),
+		File:    fn,
+	})
+
+	return err
+}
+
+// seedDB seeds the database of the --env section of the config file by
+// executing, in a single transaction, the seed files in seedsDir that have
+// not been executed, and the repeatable seed files that have changed. If there
+// is no seedsDir, seedDB does nothing.
+func seedDB(cmd *cobra.Command, args []string) error {
+	seeds, err := seed.LoadAll(seedsDir)
+	if errors.Is(err, os.ErrNotExist) {
+		emit(event{
+			Event:   "seeds_skipped",
+			Message: fmt.Sprintf("No %s directory; skipping seeds.", seedsDir),
+		})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var srv dbServer
+	srv.initFromConfigEnv(seedEnv)
+	if srv.host == "" {
+		return fmt.Errorf("no %q section in the config file", seedEnv)
+	}
+
+	// Timestamp command start.
+	start := time.Now()
+
+	// Connect to the database.
+	ctx := context.Background()
+	pool, err := connectMigrationPool(ctx, srv)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	// Fetch the checksums of the seeds that have been executed.
+	err = createSchemaSeedsTable(ctx, pool)
+	if err != nil {
+		return err
+	}
+	executed, err := fetchSeedChecksums(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	// Execute the seeds in a transaction.
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	n := 0
+	for _, s := range seeds {
+		checksum, ok := executed[s.Key()]
+		if ok && checksum == s.Checksum() {
+			continue
+		}
+		if ok && !s.Repeatable() {
+			err = fmt.Errorf("seed %s has changed since it was executed; restore it, or add a new seed", s.FileName())
+			return withExitCode(exitChecksumMismatch, err)
+		}
+
+		_, err = tx.Exec(ctx, s.SQL())
+		if err != nil {
+			return fmt.Errorf("could not execute seed %s: %w", s.FileName(), err)
+		}
+		_, err = tx.Exec(ctx, upsertSeedSQL(), s.Key(), s.Checksum())
+		if err != nil {
+			return err
+		}
+		emit(event{
+			Event:   "seed_executed",
+			Message: fmt.Sprintf("Executed seed %q.", s.FileName()),
+			Seed:    s.Key(),
+		})
+		n++
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Timestamp command end.
+	duration := time.Since(start)
+
+	emit(event{
+		Event:      "database_seeded",
+		Message:    fmt.Sprintf("Database %q seeded with %d seeds. Command completed in %s.", srv.dbName, n, duration),
+		Database:   srv.dbName,
+		Count:      n,
+		DurationMS: durationMS(duration),
+	})
+
+	return nil
+}
+
+// schemaSeedsTable returns the quoted, and possibly schema-qualified, name of
+// the schema_seeds table set by "seed.schema_seeds_table" in the config file.
+func schemaSeedsTable() pgx.Identifier {
+	name := viper.GetString("seed.schema_seeds_table")
+	if name == "" {
+		name = defaultSchemaSeedsTable
+	}
+
+	return pgx.Identifier(strings.Split(name, "."))
+}
+
+// createSchemaSeedsTable creates a schema_seeds table, and its schema, if it
+// does not already exist.
+func createSchemaSeedsTable(ctx context.Context, pool *pgxpool.Pool) error {
+	table := schemaSeedsTable()
+	if len(table) > 1 {
+		schema := pgx.Identifier(table[:len(table)-1]).Sanitize()
+		_, err := pool.Exec(ctx, "CREATE SCHEMA IF NOT EXISTS "+schema+";")
+		if err != nil {
+			return err
+		}
+	}
+
+	sql := `CREATE TABLE IF NOT EXISTS ` + table.Sanitize() + ` (
+		seed text NOT NULL,
+		checksum text NOT NULL,
+		executed_at timestamp(6) without time zone NOT NULL,
+		PRIMARY KEY (seed)
+	);`
+
+	_, err := pool.Exec(ctx, sql)
+
+	return err
+}
+
+// upsertSeedSQL returns a statement that records the checksum of an executed
+// seed in the schema_seeds table.
+func upsertSeedSQL() string {
+	return "INSERT INTO " + schemaSeedsTable().Sanitize() + ` (seed, checksum, executed_at) VALUES ($1, $2, now())
+	ON CONFLICT (seed) DO UPDATE SET checksum = EXCLUDED.checksum, executed_at = EXCLUDED.executed_at;`
+}
+
+// fetchSeedChecksums fetches the checksum of each executed seed, keyed by
+// seed.
+func fetchSeedChecksums(ctx context.Context, pool *pgxpool.Pool) (map[string]string, error) {
+	checksums := make(map[string]string)
+
+	rows, err := pool.Query(ctx, "SELECT seed, checksum FROM "+schemaSeedsTable().Sanitize()+";")
+	if err != nil {
+		return checksums, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s, c string
+		err = rows.Scan(&s, &c)
+		if err != nil {
+			return checksums, err
+		}
+		checksums[s] = c
+	}
+
+	return checksums, rows.Err()
+}
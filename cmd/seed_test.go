@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/seed"
+	"github.com/spf13/cobra"
+)
+
+// Unit test seedDB() without a seeds directory
+func TestSeedDBWithoutSeedsDir(t *testing.T) {
+	err := seedDB(&cobra.Command{}, nil)
+	if err != nil {
+		t.Errorf("want nil; got %v", err)
+	}
+}
+
+// Unit test generateSeed()
+func TestGenerateSeed(t *testing.T) {
+	cmd := &cobra.Command{}
+	defer os.RemoveAll(seedsDir) // Do cleanup
+	defer func() { seedRepeatable = false }()
+
+	err := generateSeed(cmd, []string{"Countries"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seedRepeatable = true
+	err = generateSeed(cmd, []string{"Statuses"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seeds, err := seed.LoadAll(seedsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seeds) != 2 {
+		t.Fatalf("want 2 seeds; got %d", len(seeds))
+	}
+	if !strings.HasSuffix(seeds[0].FileName(), "_countries.sql") || seeds[0].Repeatable() {
+		t.Errorf("want a versioned countries seed; got %q", seeds[0].FileName())
+	}
+	if exp, act := "R_statuses.sql", seeds[1].FileName(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	// A seed requires a name.
+	err = generateSeed(cmd, nil)
+	if err == nil {
+		t.Errorf("want error; got nil")
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+)
+
+// Reset options set by command flags.
+var (
+	resetMigrate bool
+	resetSeed    bool
+)
+
+func init() {
+	dbCmd.AddCommand(setupDBCmd)
+	dbCmd.AddCommand(prepareDBCmd)
+
+	resetDBCmd.Flags().BoolVar(&resetMigrate, "migrate", false, "migrate the database after creating it")
+	resetDBCmd.Flags().BoolVar(&resetSeed, "seed", false, "migrate and seed the database after creating it")
+}
+
+// setupDBCmd ...
+var setupDBCmd = &cobra.Command{
+	Use:   "setup",
+	Short: `Create the database if it does not exist, then migrate and seed it.`,
+	Long: `Create the database with the name specificed by the "database" attribute in the config file if
+	it does not exist, then migrate it and seed it with the seed files in the "` + seedsDir + `" directory,
+	as by "db seed".`,
+	RunE: setupDB,
+}
+
+// prepareDBCmd ...
+var prepareDBCmd = &cobra.Command{
+	Use:   "prepare",
+	Short: `Create the database if it does not exist, then migrate it.`,
+	Long: `Create the database with the name specificed by the "database" attribute in the config file if
+	it does not exist, then migrate it. Running it again does nothing unless there are new migrations.`,
+	RunE: prepareDB,
+}
+
+// setupDB creates the database if it does not exist, then migrates and seeds
+// it.
+func setupDB(cmd *cobra.Command, args []string) error {
+	err := prepareDB(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	return seedDB(cmd, args)
+}
+
+// prepareDB creates the database if it does not exist, then migrates it.
+func prepareDB(cmd *cobra.Command, args []string) error {
+	err := ensureDB(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	return migrateDB(cmd, args)
+}
+
+// ensureDB creates the database with the name specificed by the "database"
+// attribute in the viper config, unless it already exists.
+func ensureDB(cmd *cobra.Command, args []string) error {
+	var srv dbServer
+	srv.initFromConfig()
+
+	// Use the name of the database as createDB converts it to snake_case.
+	database := sqlt.Database{}
+	database.SetName(srv.dbName)
+	dbName := database.Name()
+
+	// Connect to the database server.
+	srv.dbName = "" // dbName should be blank before getting DSN.
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, srv.dsn())
+	if err != nil {
+		return err
+	}
+	exists, err := dbExists(ctx, conn, dbName)
+	conn.Close(ctx)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		emit(event{
+			Event:    "database_exists",
+			Message:  fmt.Sprintf("Database %q already exists.", dbName),
+			Database: dbName,
+		})
+		return nil
+	}
+
+	return createDB(cmd, args)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Test that ensureDB() finds a database whose configured name is not in
+// snake_case.
+func TestEnsureDB(t *testing.T) {
+	// Set up arguments.
+	cmd := &cobra.Command{}
+	args := make([]string, 0)
+
+	// Initialize configuration from config file.
+	initConfig()
+	dbForce = true // Do not ask for confirmation.
+	defer func() { dbForce = false }()
+
+	// Configure a database name that is not in snake_case.
+	dbName := viper.GetString("development.database")
+	viper.Set("development.database", "monarchEnsureTest")
+	defer viper.Set("development.database", dbName)
+
+	// Run ensureDB() twice: it creates the database, then finds it.
+	for i := 0; i < 2; i++ {
+		err := ensureDB(cmd, args)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Do cleanup.
+	err := dropDB(cmd, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -2,24 +2,69 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/kevinsapp/monarch/pkg/sqlt"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Table options set by command flags.
+var (
+	tablePrimaryKey       string
+	tableNoTimestamps     bool
+	tableTimestampTZ      bool
+	tableTimestampDefault bool
+	tableUpdatedAtTrigger bool
+	tablePartitionBy      string
+	tableComment          string
 )
 
 func init() {
 	createCmd.AddCommand(createTableCmd)
 	dropCmd.AddCommand(dropTableCmd)
 	renameCmd.AddCommand(renameTableCmd)
+
+	createTableCmd.Flags().StringVar(&tablePrimaryKey, "primary-key", "", `primary key: bigserial, identity, uuid, none or a comma-separated column list
+(default is the "generate.primary_key" config setting, or bigserial)`)
+	createTableCmd.Flags().BoolVar(&tableNoTimestamps, "no-timestamps", false, "omit the created_at and updated_at columns")
+	createTableCmd.Flags().BoolVar(&tableTimestampTZ, "timestamptz", false, "use timestamp with time zone for the timestamp columns")
+	createTableCmd.Flags().BoolVar(&tableTimestampDefault, "timestamp-default", false, "give the timestamp columns a DEFAULT now()")
+	createTableCmd.Flags().BoolVar(&tableUpdatedAtTrigger, "updated-at-trigger", false, "create a trigger that sets updated_at on every UPDATE")
+	createTableCmd.Flags().StringVar(&tableComment, "comment", "", "comment on the table")
+	createTableCmd.Flags().StringVar(&tablePartitionBy, "partition-by", "", "partition the table, e.g. range(created_at), list(region) or hash(id)")
 }
 
 // createTableCmd generates an "up" migration file to create a table and a "down" migration
 // file to drop that table.
 var createTableCmd = &cobra.Command{
-	Use:   "table [name]",
+	Use:   "table [name] [ [colName:type[:comment]] ... ]",
 	Short: "Generate a migration file to create a table named [name].",
-	RunE:  createTableMigration,
+	Long: `Generate a migration file to create a table named [name].
+	The primary key is an "id bigserial" column unless --primary-key is given:
+
+	  identity   id bigint GENERATED ALWAYS AS IDENTITY
+	  uuid       id uuid with a gen_random_uuid() default (PostgreSQL 13, or pgcrypto)
+	  none       no primary key
+	  a,b        a composite primary key on columns a and b
+
+	The created_at and updated_at columns can be omitted with --no-timestamps, typed as
+	timestamptz with --timestamptz and given a DEFAULT now() with --timestamp-default.
+	With --updated-at-trigger, a BEFORE UPDATE trigger keeps updated_at current. The trigger
+	calls a shared set_updated_at() function, which is created by a separate migration the
+	first time it is needed.
+
+	With --partition-by, the table is a partitioned table whose partitions are created by
+	"create partition" or "create partitions". The partition key columns are added to the
+	primary key, which cannot include an expression, so an expression key requires
+	--primary-key none.
+
+	Project-wide defaults can be set in the config file with "generate.primary_key",
+	"generate.timestamps", "generate.timestamptz", "generate.timestamp_default" and
+	"generate.updated_at_trigger".`,
+	RunE: createTableMigration,
 }
 
 // dropTableCmd generates an "up" migration file to drop a table.
@@ -52,21 +97,38 @@ func createTableMigration(cmd *cobra.Command, args []string) error {
 	tableName := args[0]
 	t := new(sqlt.Table)
 	t.SetName(tableName)
+	t.SetComment(tableComment)
+	setTimestamps(t)
+	if t.UpdatedAtTrigger() && !t.Timestamps() {
+		return errors.New("--updated-at-trigger requires timestamps")
+	}
 
 	// If column args are present, parse args and add columns to table.
 	if len(args) > 1 {
 		// Add columns to table object
 		for _, v := range args[1:] {
-			nameType := strings.Split(v, ":")
-
-			col := sqlt.Column{}
-			col.SetName(nameType[0])
-			col.SetType(nameType[1])
+			col, err := parseColumnSpec(v)
+			if err != nil {
+				return err
+			}
 
 			t.AddColumn(col)
 		}
 	}
 
+	// Set the primary key, which may name the columns, and the partitioning,
+	// which depends on the primary key.
+	err := setPrimaryKey(t, tablePrimaryKey)
+	if err != nil {
+		return err
+	}
+	if tablePartitionBy != "" {
+		err = setPartitionBy(t, tablePartitionBy)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Process SQL template for "up" migration.
 	upSQL, err := sqlt.ProcessTmpl(t, sqlt.CreateTableTmpl)
 	if err != nil {
@@ -79,8 +141,16 @@ func createTableMigration(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Create the shared trigger function if no migration has created it yet.
+	if t.UpdatedAtTrigger() {
+		err = createSetUpdatedAtFunctionMigration()
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create migration file.
-	err = createMigration("CreateTable_"+tableName, upSQL, downSQL)
+	err = createMigration("CreateTable_"+t.Name(), upSQL, downSQL)
 	if err != nil {
 		return err
 	}
@@ -113,7 +183,7 @@ func dropTableMigration(cmd *cobra.Command, args []string) error {
 	// }
 
 	// Create migration file.
-	err = createMigration("DropTable_"+tableName, upSQL, "")
+	err = createMigration("DropTable_"+t.Name(), upSQL, "")
 	if err != nil {
 		return err
 	}
@@ -144,18 +214,164 @@ func renameTableMigration(cmd *cobra.Command, args []string) error {
 	}
 
 	// Process SQL template for "down" migration.
-	t.SetName(newName)      // swap name and newname
-	t.SetNewName(tableName) // swap name and newname
+	t.SetName(t.QualifiedNewName()) // swap name and newname
+	t.SetNewName(tableName)         // swap name and newname
 	downSQL, err := sqlt.ProcessTmpl(t, sqlt.RenameTableTmpl)
 	if err != nil {
 		return err
 	}
 
 	// Create migration file.
-	err = createMigration("RenameTable_"+tableName, upSQL, downSQL)
+	err = createMigration("RenameTable_"+t.QualifiedNewName(), upSQL, downSQL)
 	if err != nil {
 		return err
 	}
 
 	return err
 }
+
+// setPrimaryKey sets the primary key of a table from a --primary-key option.
+// If the option is blank, the "generate.primary_key" config setting is used.
+// The option is either a strategy, or a comma-separated list of columns for a
+// composite primary key. Each of those columns must be one of the columns of
+// the table, so that a misspelled strategy is an error.
+func setPrimaryKey(t *sqlt.Table, option string) error {
+	if option == "" {
+		option = viper.GetString("generate.primary_key")
+	}
+
+	switch strings.ToLower(option) {
+	case "":
+		t.SetPrimaryKey(sqlt.BigserialPrimaryKey)
+		return nil
+	case sqlt.BigserialPrimaryKey, sqlt.IdentityPrimaryKey, sqlt.UUIDPrimaryKey, sqlt.NoPrimaryKey:
+		t.SetPrimaryKey(option)
+		return nil
+	}
+
+	// Check that each composite primary key column is a column of the table.
+	names := strings.Split(option, ",")
+	for _, n := range names {
+		var k sqlt.Column
+		k.SetName(n)
+		if !hasColumn(t, k.Name()) {
+			return fmt.Errorf("invalid primary key %q: %q is not a column of the table; want %s, %s, %s, %s, or a comma-separated list of columns",
+				option, k.Name(), sqlt.BigserialPrimaryKey, sqlt.IdentityPrimaryKey, sqlt.UUIDPrimaryKey, sqlt.NoPrimaryKey)
+		}
+	}
+	t.SetPrimaryKeyColumns(names)
+
+	return nil
+}
+
+// hasColumn reports whether a table has a column, either from the column
+// specs or, if the table has timestamps, created_at or updated_at, or if its
+// primary key strategy generates it, id.
+func hasColumn(t *sqlt.Table, name string) bool {
+	if t.Timestamps() && (name == "created_at" || name == "updated_at") {
+		return true
+	}
+	switch t.PrimaryKey() {
+	case sqlt.BigserialPrimaryKey, sqlt.IdentityPrimaryKey, sqlt.UUIDPrimaryKey:
+		if name == "id" {
+			return true
+		}
+	}
+
+	cols := t.Columns()
+	for i := range cols {
+		if cols[i].Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// partitionByRegexp matches a partitioning option, e.g. `range(created_at)`,
+// and captures the strategy and the partition key.
+var partitionByRegexp = regexp.MustCompile(`^\s*(?i:(range|list|hash))\s*\((.+)\)\s*$`)
+
+// setPartitionBy sets the partitioning strategy and partition key of a table
+// from a partitioning option, e.g. `range(created_at)`. The key is a
+// comma-separated list of columns of the table and parenthesized expressions.
+func setPartitionBy(t *sqlt.Table, option string) error {
+	match := partitionByRegexp.FindStringSubmatch(option)
+	if match == nil {
+		return fmt.Errorf("invalid --partition-by %q: want range(key), list(key) or hash(key)", option)
+	}
+
+	// Split the key on commas that are not inside parentheses.
+	key := make([]string, 0)
+	depth, start := 0, 0
+	spec := match[2] + ","
+	for i, r := range spec {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				key = append(key, strings.TrimSpace(spec[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	for _, k := range key {
+		if strings.HasPrefix(k, "(") {
+			if t.PrimaryKey() != sqlt.NoPrimaryKey {
+				return fmt.Errorf("partition key expression %s requires --primary-key none", k)
+			}
+			continue
+		}
+
+		// Check that a partition key column is a column of the table.
+		var c sqlt.Column
+		c.SetName(k)
+		if !hasColumn(t, c.Name()) {
+			return fmt.Errorf("invalid --partition-by %q: %q is not a column of the table", option, c.Name())
+		}
+	}
+	t.SetPartitionBy(match[1], key)
+
+	return nil
+}
+
+// setTimestamps sets the timestamp options of a table from command flags. A
+// flag that is not set falls back to its "generate.*" config setting.
+func setTimestamps(t *sqlt.Table) {
+	timestamps := !tableNoTimestamps
+	if timestamps && viper.IsSet("generate.timestamps") {
+		timestamps = viper.GetBool("generate.timestamps")
+	}
+
+	t.SetTimestamps(timestamps)
+	t.SetTimestampTZ(tableTimestampTZ || viper.GetBool("generate.timestamptz"))
+	t.SetTimestampDefault(tableTimestampDefault || viper.GetBool("generate.timestamp_default"))
+	t.SetUpdatedAtTrigger(tableUpdatedAtTrigger || viper.GetBool("generate.updated_at_trigger"))
+}
+
+// createSetUpdatedAtFunctionMigration creates a migration file to create the
+// set_updated_at() trigger function, unless one already exists.
+func createSetUpdatedAtFunctionMigration() error {
+	const name = "CreateFunction_set_updated_at"
+
+	m, err := findLatestMigration(name)
+	if err != nil || m != nil {
+		return err
+	}
+
+	// Process SQL templates, which may be overridden by template files.
+	upSQL, err := sqlt.ProcessTmpl(nil, sqlt.CreateSetUpdatedAtFunctionTmpl)
+	if err != nil {
+		return err
+	}
+	downSQL, err := sqlt.ProcessTmpl(nil, sqlt.DropSetUpdatedAtFunctionTmpl)
+	if err != nil {
+		return err
+	}
+
+	return createMigration(name, upSQL, downSQL)
+}
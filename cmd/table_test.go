@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
 	"github.com/spf13/cobra"
 )
 
@@ -248,3 +250,326 @@ func TestRenameTableMigrations(t *testing.T) {
 		t.Errorf("\nwant %q;\ngot %q\n", exp, act)
 	}
 }
+
+// Unit test createTableMigration() with each --primary-key option.
+func TestCreateTableMigrationPrimaryKey(t *testing.T) {
+	cases := []struct {
+		option string
+		upSQL  string
+	}{
+		{"identity", `CREATE TABLE users (
+	PRIMARY KEY (id),
+	id bigint GENERATED ALWAYS AS IDENTITY,
+
+	-- Specify additional fields here.
+
+	-- Timestamps
+	created_at timestamp(6) without time zone NOT NULL,
+	updated_at timestamp(6) without time zone NOT NULL
+);`},
+		{"UUID", `CREATE TABLE users (
+	PRIMARY KEY (id),
+	id uuid NOT NULL DEFAULT gen_random_uuid(),
+
+	-- Specify additional fields here.
+
+	-- Timestamps
+	created_at timestamp(6) without time zone NOT NULL,
+	updated_at timestamp(6) without time zone NOT NULL
+);`},
+		{"none", `CREATE TABLE users (
+
+	-- Specify additional fields here.
+
+	-- Timestamps
+	created_at timestamp(6) without time zone NOT NULL,
+	updated_at timestamp(6) without time zone NOT NULL
+);`},
+		{"userID,groupID", `CREATE TABLE users (
+	PRIMARY KEY (user_id, group_id),
+	user_id bigint,
+	group_id bigint,
+
+	-- Specify additional fields here.
+
+	-- Timestamps
+	created_at timestamp(6) without time zone NOT NULL,
+	updated_at timestamp(6) without time zone NOT NULL
+);`},
+		{"idA,createdAt", `CREATE TABLE users (
+	PRIMARY KEY (id_a, created_at),
+	id_a bigint,
+
+	-- Specify additional fields here.
+
+	-- Timestamps
+	created_at timestamp(6) without time zone NOT NULL,
+	updated_at timestamp(6) without time zone NOT NULL
+);`},
+	}
+
+	for _, c := range cases {
+		// Create a migrations directory.
+		cmd := &cobra.Command{}
+		mkdirMigrations(cmd, nil)
+
+		// Run createTableMigration() with --primary-key set.
+		tablePrimaryKey = c.option
+		args := []string{"users"}
+		switch c.option {
+		case "userID,groupID":
+			args = append(args, "userID:bigint", "groupID:bigint")
+		case "idA,createdAt":
+			args = append(args, "idA:bigint")
+		}
+		err := createTableMigration(cmd, args)
+		tablePrimaryKey = ""
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := readMigrationsHelper(1, t)[0]
+
+		// Verify that the upSQL is as expected
+		if exp, act := c.upSQL, m.UpSQL(); exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+
+		os.RemoveAll(migrationsDir) // Do cleanup
+	}
+}
+
+// Unit test createTableMigration() with a --primary-key option that is neither
+// a strategy nor a list of columns of the table.
+func TestCreateTableMigrationInvalidPrimaryKey(t *testing.T) {
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer func() { tablePrimaryKey = "" }()
+
+	for _, option := range []string{"uuidd", "userID,groupID"} {
+		tablePrimaryKey = option
+		err := createTableMigration(cmd, []string{"users", "userID:bigint"})
+		if err == nil || !strings.Contains(err.Error(), sqlt.UUIDPrimaryKey) {
+			t.Errorf("%q: want error naming the strategies; got %v", option, err)
+		}
+	}
+
+	// The timestamp columns are columns of the table only with timestamps.
+	tablePrimaryKey = "userID,createdAt"
+	tableNoTimestamps = true
+	defer func() { tableNoTimestamps = false }()
+	err := createTableMigration(cmd, []string{"users", "userID:bigint"})
+	if err == nil || !strings.Contains(err.Error(), `"created_at"`) {
+		t.Errorf("want error naming created_at; got %v", err)
+	}
+}
+
+// Unit test createTableMigration() with the timestamp options.
+func TestCreateTableMigrationTimestamps(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetTableFlagsHelper()
+
+	// Run createTableMigration() with --timestamptz and --timestamp-default.
+	tableTimestampTZ = true
+	tableTimestampDefault = true
+	err := createTableMigration(cmd, []string{"users", "name:text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run createTableMigration() with --no-timestamps.
+	resetTableFlagsHelper()
+	tableNoTimestamps = true
+	err = createTableMigration(cmd, []string{"tags", "name:text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(2, t)
+
+	exp := `CREATE TABLE users (
+	PRIMARY KEY (id),
+	id bigserial NOT NULL,
+	name text,
+
+	-- Specify additional fields here.
+
+	-- Timestamps
+	created_at timestamp(6) with time zone NOT NULL DEFAULT now(),
+	updated_at timestamp(6) with time zone NOT NULL DEFAULT now()
+);`
+	if act := ms[0].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+
+	exp = `CREATE TABLE tags (
+	PRIMARY KEY (id),
+	id bigserial NOT NULL,
+	name text
+
+	-- Specify additional fields here.
+);`
+	if act := ms[1].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+}
+
+// Unit test createTableMigration() with --updated-at-trigger.
+func TestCreateTableMigrationUpdatedAtTrigger(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetTableFlagsHelper()
+
+	// Create two tables; the trigger function should only be created once.
+	tableUpdatedAtTrigger = true
+	for _, v := range []string{"users", "posts"} {
+		err := createTableMigration(cmd, []string{v})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ms := readMigrationsHelper(3, t)
+
+	// Verify the trigger function migration.
+	exp := "create_function_set_updated_at"
+	if act := ms[0].Name(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+	if act := ms[0].DownSQL(); act != "DROP FUNCTION IF EXISTS set_updated_at();" {
+		t.Errorf("unexpected downSQL %q", act)
+	}
+
+	// Verify the trigger is created with each table.
+	exp = `CREATE TRIGGER posts_updated_at_mnrk_trg
+	BEFORE UPDATE ON posts
+	FOR EACH ROW EXECUTE FUNCTION set_updated_at();`
+	if act := ms[2].UpSQL(); !strings.HasSuffix(act, exp) {
+		t.Errorf("\nwant suffix %q\n got %q\n", exp, act)
+	}
+
+	// The trigger requires an updated_at column.
+	tableNoTimestamps = true
+	err := createTableMigration(cmd, []string{"tags"})
+	if err == nil {
+		t.Error("want error; got nil")
+	}
+}
+
+// Unit test createTableMigration() with --partition-by
+func TestCreateTableMigrationPartitionBy(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetTableFlagsHelper()
+
+	tablePartitionBy = "range(created_at)"
+	err := createTableMigration(cmd, []string{"events"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(1, t)
+
+	exp := `CREATE TABLE events (
+	PRIMARY KEY (id, created_at),
+	id bigserial NOT NULL,
+
+	-- Specify additional fields here.
+
+	-- Timestamps
+	created_at timestamp(6) without time zone NOT NULL,
+	updated_at timestamp(6) without time zone NOT NULL
+) PARTITION BY RANGE (created_at);`
+	if act := ms[0].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+
+	// An expression key cannot be part of the primary key.
+	tablePartitionBy = "list((lower(region)))"
+	err = createTableMigration(cmd, []string{"orders"})
+	if err == nil {
+		t.Error("want error for an expression key; got nil")
+	}
+	tablePrimaryKey = "none"
+	err = createTableMigration(cmd, []string{"orders"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	// A key column must be a column of the table.
+	tablePrimaryKey = ""
+	tablePartitionBy = "hash(id)"
+	err = createTableMigration(cmd, []string{"orders"})
+	if err != nil {
+		t.Error(err)
+	}
+	tableNoTimestamps = true
+	tablePartitionBy = "range(created_at)"
+	err = createTableMigration(cmd, []string{"events"})
+	if err == nil || !strings.Contains(err.Error(), `"created_at"`) {
+		t.Errorf("want error naming created_at; got %v", err)
+	}
+	err = createTableMigration(cmd, []string{"events", "createdAt:timestamptz"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	tablePartitionBy = "zigzag(id)"
+	err = createTableMigration(cmd, []string{"orders"})
+	if err == nil {
+		t.Error("want error for an invalid strategy; got nil")
+	}
+}
+
+// Unit test createTableMigration() with comments
+func TestCreateTableMigrationComments(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetTableFlagsHelper()
+
+	tableNoTimestamps = true
+	tableComment = "People who can log in"
+	err := createTableMigration(cmd, []string{"users", "email:citext:Login name", "age:int"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(1, t)
+
+	exp := `CREATE TABLE users (
+	PRIMARY KEY (id),
+	id bigserial NOT NULL,
+	email citext,
+	age int
+
+	-- Specify additional fields here.
+);
+
+COMMENT ON TABLE users IS 'People who can log in';
+
+COMMENT ON COLUMN users.email IS 'Login name';`
+	if act := ms[0].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+}
+
+// resetTableFlagsHelper resets the table options set by command flags.
+func resetTableFlagsHelper() {
+	tablePrimaryKey = ""
+	tableNoTimestamps = false
+	tableTimestampTZ = false
+	tableTimestampDefault = false
+	tableUpdatedAtTrigger = false
+	tablePartitionBy = ""
+	tableComment = ""
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+)
+
+// templatesDir is the directory, in the current working directory, of the
+// template files that override the SQL templates of the generators.
+const templatesDir string = "templates"
+
+// templatesEject is set by the --eject flag.
+var templatesEject bool
+
+func init() {
+	generateCmd.AddCommand(generateTemplatesCmd)
+
+	generateTemplatesCmd.Flags().BoolVar(&templatesEject, "eject", false, `copy the default templates into the "`+templatesDir+`" directory for editing`)
+}
+
+// generateTemplatesCmd ...
+var generateTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: `List the SQL templates, or with --eject, copy them into the "` + templatesDir + `" directory.`,
+	Long: `List the SQL templates of the generators and whether a template file in the "` + templatesDir + `"
+	directory overrides each of them. A template file, e.g. ` + templatesDir + `/create_table.sql.tmpl, is a
+	text/template applied to the same data as the template it overrides.
+
+	With --eject, the default templates are copied into the "` + templatesDir + `" directory, except
+	for those that already have a template file. Delete the files that you do not change, so that
+	they keep up with new versions of monarch.`,
+	RunE: generateTemplates,
+}
+
+// generateTemplates lists the SQL templates, or copies the default templates
+// into templatesDir.
+func generateTemplates(cmd *cobra.Command, args []string) error {
+	if templatesEject {
+		err := fileutil.MkdirP(templatesDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sqlt.TemplateFileNames() {
+		path := templatesDir + "/" + name
+		_, err := os.Stat(path)
+		exists := err == nil
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		// List the template.
+		if !templatesEject {
+			status := "default"
+			if exists {
+				status = "overridden"
+			}
+			emit(event{
+				Event:   "template_listed",
+				Message: fmt.Sprintf("%-40s %s", name, status),
+				File:    path,
+				Status:  status,
+			})
+			continue
+		}
+
+		// Copy the default template, unless it is overridden.
+		if exists {
+			emit(event{
+				Event:   "template_skipped",
+				Message: fmt.Sprintf("Skipped %s, which already exists", path),
+				File:    path,
+			})
+			continue
+		}
+		text, _ := sqlt.DefaultTemplate(name)
+		err = fileutil.CreateAndWriteString(path, text)
+		if err != nil {
+			return err
+		}
+		emit(event{
+			Event:   "template_ejected",
+			Message: fmt.Sprintf("Created %s", path),
+			File:    path,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+)
+
+// Unit test generateTemplates() with --eject
+func TestGenerateTemplatesEject(t *testing.T) {
+	cmd := &cobra.Command{}
+	defer os.RemoveAll(templatesDir) // Do cleanup
+	defer func() { templatesEject = false }()
+
+	// An existing template file is not overwritten.
+	fileutil.MkdirP(templatesDir)
+	custom := "DROP TABLE IF EXISTS {{.Name}};"
+	err := fileutil.CreateAndWriteString(templatesDir+"/drop_table.sql.tmpl", custom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	templatesEject = true
+	err = generateTemplates(cmd, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range sqlt.TemplateFileNames() {
+		act, err := fileutil.ReadFileAsString(templatesDir + "/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp, _ := sqlt.DefaultTemplate(name)
+		if name == "drop_table.sql.tmpl" {
+			exp = custom
+		}
+		if exp != act {
+			t.Errorf("%s: want %q; got %q", name, exp, act)
+		}
+	}
+}
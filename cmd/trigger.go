@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Trigger options set by command flags.
+var (
+	trgName    string
+	trgTiming  string
+	trgForEach string
+	trgWhen    string
+)
+
+func init() {
+	createCmd.AddCommand(createTriggerCmd)
+	dropCmd.AddCommand(dropTriggerCmd)
+
+	addTriggerFlags(createTriggerCmd.Flags())
+	addTriggerFlags(dropTriggerCmd.Flags())
+}
+
+// addTriggerFlags defines the flags shared by the trigger commands.
+func addTriggerFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&trgName, "name", "", "trigger name (default is [tablename]_[function]_mnrk_trg)")
+	fs.StringVar(&trgTiming, "timing", "before", "when the trigger fires: before, after or instead-of")
+	fs.StringVar(&trgForEach, "for-each", "row", "fire the trigger for each row or statement")
+	fs.StringVar(&trgWhen, "when", "", "condition that determines whether the trigger function is executed")
+}
+
+// createTriggerCmd generates a migration file to create a trigger on a table.
+var createTriggerCmd = &cobra.Command{
+	Use:   "trigger [tablename] [function] [ [event[:column,...]] ... ]",
+	Short: "Generate a migration file to create a trigger on a table.",
+	Long: `Generate a migration file to create a trigger that executes [function] on a table. Each
+	event is insert, update, delete or truncate; an update event may be limited to a list of
+	columns. The function is created with "create function --returns trigger". For example:
+
+	  monarch g m create trigger posts stampUser insert update:title,body
+	  monarch g m create trigger posts auditPost delete --timing after --when "OLD.published"`,
+	RunE: createTriggerMigration,
+}
+
+// dropTriggerCmd generates a migration file to drop a trigger from a table.
+var dropTriggerCmd = &cobra.Command{
+	Use:   "trigger [tablename] [function] [ [event[:column,...]] ... ]",
+	Short: "Generate a migration file to drop a trigger from a table.",
+	Long: `Generate a migration file to drop a trigger from a table. The arguments and flags should
+	match those used to create the trigger so that the "down" migration can restore it.`,
+	RunE: dropTriggerMigration,
+}
+
+// createTriggerMigration creates a migration file to create a trigger on a table.
+func createTriggerMigration(cmd *cobra.Command, args []string) error {
+	// Set trigger data.
+	trg, err := triggerFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(trg, sqlt.CreateTriggerTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(trg, sqlt.DropTriggerTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("CreateTriggerOn_"+trg.TableName(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// dropTriggerMigration creates a migration file to drop a trigger from a table.
+func dropTriggerMigration(cmd *cobra.Command, args []string) error {
+	// Set trigger data.
+	trg, err := triggerFromArgs(args)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(trg, sqlt.DropTriggerTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Process SQL template for "down" migration.
+	downSQL, err := sqlt.ProcessTmpl(trg, sqlt.CreateTriggerTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("DropTriggerFrom_"+trg.TableName(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// triggerFromArgs configures a trigger from command arguments and flags.
+func triggerFromArgs(args []string) (*sqlt.Trigger, error) {
+	// Caller should supply a table name as the first argument, a function
+	// name as the second argument and one or more events as the following
+	// arguments.
+	if len(args) < 3 {
+		return nil, errors.New("requires tablename and function arguments followed by one or more event arguments")
+	}
+
+	trg := new(sqlt.Trigger)
+	trg.SetTableName(args[0])
+	trg.SetFunction(args[1])
+	trg.SetName(trgName)
+	trg.SetWhen(trgWhen)
+
+	for _, v := range args[2:] {
+		eventCols := strings.SplitN(v, ":", 2)
+		e := sqlt.TriggerEvent{}
+		e.SetEvent(eventCols[0])
+		switch e.Event() {
+		case "INSERT", "DELETE", "TRUNCATE":
+			if len(eventCols) > 1 {
+				return nil, fmt.Errorf("only an update event can have columns, not %q", v)
+			}
+		case "UPDATE":
+			if len(eventCols) > 1 {
+				for _, c := range strings.Split(eventCols[1], ",") {
+					e.AddColumn(c)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unsupported trigger event %q", eventCols[0])
+		}
+		trg.AddEvent(e)
+	}
+
+	switch t := strings.ToUpper(strings.NewReplacer("-", " ", "_", " ").Replace(trgTiming)); t {
+	case "", "BEFORE", "AFTER", "INSTEAD OF":
+		trg.SetTiming(t)
+	default:
+		return nil, fmt.Errorf("unsupported trigger timing %q", trgTiming)
+	}
+
+	switch l := strings.ToUpper(trgForEach); l {
+	case "", "ROW", "STATEMENT":
+		trg.SetForEach(l)
+	default:
+		return nil, fmt.Errorf("a trigger fires for each row or statement, not %q", trgForEach)
+	}
+
+	return trg, nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// Unit test createTriggerMigration() and dropTriggerMigration()
+func TestCreateAndDropTriggerMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetTriggerFlagsHelper()
+
+	trgTiming = "after"
+	trgWhen = "OLD.title IS DISTINCT FROM NEW.title"
+	args := []string{"posts", "auditPost", "insert", "update:title,bodyText"}
+	err := createTriggerMigration(cmd, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dropTriggerMigration(cmd, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(2, t)
+
+	createSQL := `CREATE TRIGGER posts_audit_post_mnrk_trg
+	AFTER INSERT OR UPDATE OF title, body_text ON posts
+	FOR EACH ROW WHEN (OLD.title IS DISTINCT FROM NEW.title) EXECUTE FUNCTION audit_post();`
+	dropSQL := `DROP TRIGGER IF EXISTS posts_audit_post_mnrk_trg ON posts;`
+
+	cases := [][]string{
+		{createSQL, ms[0].UpSQL()},
+		{dropSQL, ms[0].DownSQL()},
+		{dropSQL, ms[1].UpSQL()},
+		{createSQL, ms[1].DownSQL()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+}
+
+// Unit test triggerFromArgs() with invalid arguments.
+func TestTriggerFromArgsErrors(t *testing.T) {
+	defer resetTriggerFlagsHelper()
+
+	cases := []struct {
+		args   []string
+		timing string
+	}{
+		{[]string{"posts", "auditPost"}, ""},
+		{[]string{"posts", "auditPost", "select"}, ""},
+		{[]string{"posts", "auditPost", "insert:title"}, ""},
+		{[]string{"posts", "auditPost", "insert"}, "during"},
+	}
+	for _, c := range cases {
+		trgTiming = c.timing
+		_, err := triggerFromArgs(c.args)
+		if err == nil {
+			t.Errorf("%q: want error; got nil", c.args)
+		}
+	}
+}
+
+// resetTriggerFlagsHelper resets the trigger options set by command flags.
+func resetTriggerFlagsHelper() {
+	trgName = ""
+	trgTiming = ""
+	trgForEach = ""
+	trgWhen = ""
+}
@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +14,6 @@ var versionCmd = &cobra.Command{
 	Short: "Print the version",
 	Long:  "Print the version",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("v0.1.0")
+		emit(event{Event: "version", Message: "v0.1.0"})
 	},
 }
@@ -0,0 +1,430 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+	"github.com/kevinsapp/monarch/pkg/migration"
+	"github.com/kevinsapp/monarch/pkg/sqlt"
+	"github.com/spf13/cobra"
+)
+
+// View options set by command flags.
+var (
+	viewFromFile      string
+	viewWithNoData    bool
+	viewIndexes       []string
+	viewUniqueIndexes []string
+	viewConcurrently  bool
+)
+
+// viewStmtRegexp matches a CREATE VIEW statement and captures its query.
+var viewStmtRegexp = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:MATERIALIZED\s+)?VIEW\s+\S+\s+AS\s+(.*?)(?:\s+WITH\s+(?:NO\s+)?DATA)?\s*;?$`)
+
+// indexStmtRegexp matches a CREATE INDEX statement.
+var indexStmtRegexp = regexp.MustCompile(`(?is)^CREATE\s+(?:UNIQUE\s+)?INDEX\s`)
+
+func init() {
+	createCmd.AddCommand(createViewCmd)
+	createCmd.AddCommand(createMaterializedCmd)
+	createMaterializedCmd.AddCommand(createMaterializedViewCmd)
+	dropCmd.AddCommand(dropViewCmd)
+	dropCmd.AddCommand(dropMaterializedCmd)
+	dropMaterializedCmd.AddCommand(dropMaterializedViewCmd)
+	migrationCmd.AddCommand(refreshCmd)
+	refreshCmd.AddCommand(refreshMaterializedCmd)
+	refreshMaterializedCmd.AddCommand(refreshMaterializedViewCmd)
+
+	createViewCmd.Flags().StringVar(&viewFromFile, "from-file", "", "read the query of the view from a file")
+	createMaterializedViewCmd.Flags().StringVar(&viewFromFile, "from-file", "", "read the query of the view from a file")
+	createMaterializedViewCmd.Flags().BoolVar(&viewWithNoData, "with-no-data", false, "create the view WITH NO DATA; it cannot be queried until it is refreshed")
+	createMaterializedViewCmd.Flags().StringArrayVar(&viewIndexes, "index", nil, "comma-separated columns to index (may be repeated)")
+	createMaterializedViewCmd.Flags().StringArrayVar(&viewUniqueIndexes, "unique-index", nil, "comma-separated columns for a unique index (may be repeated)")
+	refreshMaterializedViewCmd.Flags().BoolVar(&viewConcurrently, "concurrently", false, "refresh without locking out selects; requires a unique index")
+}
+
+// createMaterializedCmd ...
+var createMaterializedCmd = &cobra.Command{
+	Use: "materialized",
+}
+
+// dropMaterializedCmd ...
+var dropMaterializedCmd = &cobra.Command{
+	Use: "materialized",
+}
+
+// refreshCmd ...
+var refreshCmd = &cobra.Command{
+	Use: "refresh",
+}
+
+// refreshMaterializedCmd ...
+var refreshMaterializedCmd = &cobra.Command{
+	Use: "materialized",
+}
+
+// createViewCmd generates a migration file to create or replace a view.
+var createViewCmd = &cobra.Command{
+	Use:   "view [name] [query]",
+	Short: "Generate a migration file to create or replace a view named [name].",
+	Long: `Generate a migration file to create or replace a view named [name]. The query is given
+	as an argument or read from a file with --from-file.
+
+	If the view already exists, it is replaced with CREATE OR REPLACE VIEW and the "down"
+	migration restores the previous definition. The previous definition is taken from the
+	latest migration that created the view or, if there is none, from the pg_views catalog
+	of the development database when it is available.`,
+	RunE: createViewMigration,
+}
+
+// createMaterializedViewCmd generates a migration file to create or replace a materialized view.
+var createMaterializedViewCmd = &cobra.Command{
+	Use:   "view [name] [query]",
+	Short: "Generate a migration file to create or replace a materialized view named [name].",
+	Long: `Generate a migration file to create or replace a materialized view named [name]. The query
+	is given as an argument or read from a file with --from-file. Indexes on the view are
+	created with --index and --unique-index; a unique index is required to refresh the view
+	concurrently.
+
+	A materialized view cannot be replaced in place. If the view already exists, it is dropped
+	and created again, and the "down" migration restores the previous definition and indexes.`,
+	RunE: createMaterializedViewMigration,
+}
+
+// dropViewCmd generates a migration file to drop a view.
+var dropViewCmd = &cobra.Command{
+	Use:   "view [name]",
+	Short: "Generate a migration file to drop a view named [name].",
+	Long: `Generate a migration file to drop a view named [name]. If the definition of the view can
+	be found, the "down" migration restores it.`,
+	RunE: dropViewMigration,
+}
+
+// dropMaterializedViewCmd generates a migration file to drop a materialized view.
+var dropMaterializedViewCmd = &cobra.Command{
+	Use:   "view [name]",
+	Short: "Generate a migration file to drop a materialized view named [name].",
+	Long: `Generate a migration file to drop a materialized view named [name]. If the definition of
+	the view can be found, the "down" migration restores it.`,
+	RunE: dropMaterializedViewMigration,
+}
+
+// refreshMaterializedViewCmd generates a migration file to refresh a materialized view.
+var refreshMaterializedViewCmd = &cobra.Command{
+	Use:   "view [name]",
+	Short: "Generate a migration file to refresh a materialized view named [name].",
+	RunE:  refreshMaterializedViewMigration,
+}
+
+// createViewMigration creates a migration file to create or replace a view.
+func createViewMigration(cmd *cobra.Command, args []string) error {
+	return createView(args, false)
+}
+
+// createMaterializedViewMigration creates a migration file to create or replace a materialized view.
+func createMaterializedViewMigration(cmd *cobra.Command, args []string) error {
+	return createView(args, true)
+}
+
+// dropViewMigration creates a migration file to drop a view.
+func dropViewMigration(cmd *cobra.Command, args []string) error {
+	return dropView(args, false)
+}
+
+// dropMaterializedViewMigration creates a migration file to drop a materialized view.
+func dropMaterializedViewMigration(cmd *cobra.Command, args []string) error {
+	return dropView(args, true)
+}
+
+// refreshMaterializedViewMigration creates a migration file to refresh a materialized view.
+func refreshMaterializedViewMigration(cmd *cobra.Command, args []string) error {
+	// Caller should supply a view name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a name argument")
+	}
+
+	// Set view data.
+	v := new(sqlt.View)
+	v.SetName(args[0])
+	v.SetMaterialized(true)
+	v.SetConcurrently(viewConcurrently)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(v, sqlt.RefreshMaterializedViewTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Create migration file.
+	err = createMigration("RefreshMaterializedView_"+v.Name(), upSQL, "")
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// createView creates a migration file to create or replace a view.
+func createView(args []string, materialized bool) error {
+	// Caller should supply a view name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a name argument")
+	}
+
+	// Set view data.
+	v := new(sqlt.View)
+	v.SetName(args[0])
+	v.SetMaterialized(materialized)
+	v.SetWithNoData(viewWithNoData)
+	query, err := viewQuery(args)
+	if err != nil {
+		return err
+	}
+	v.SetQuery(query)
+
+	indexes, err := viewIndexSQL(v)
+	if err != nil {
+		return err
+	}
+
+	// Look up the definition that the view replaces, if any.
+	prev, prevIndexes, err := previousView(v)
+	if err != nil {
+		return err
+	}
+	v.SetOrReplace(prev != nil)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(v, sqlt.CreateViewTmpl)
+	if err != nil {
+		return err
+	}
+	upSQL = joinSQL(append([]string{upSQL}, indexes...)...)
+
+	// Process SQL template for "down" migration.
+	dropSQL, err := sqlt.ProcessTmpl(v, sqlt.DropViewTmpl)
+	if err != nil {
+		return err
+	}
+	downSQL := dropSQL
+	if prev != nil {
+		prev.SetOrReplace(true)
+		prevSQL, err := sqlt.ProcessTmpl(prev, sqlt.CreateViewTmpl)
+		if err != nil {
+			return err
+		}
+		downSQL = joinSQL(append([]string{prevSQL}, prevIndexes...)...)
+
+		// A materialized view is dropped and created again.
+		if materialized {
+			upSQL = joinSQL(dropSQL, upSQL)
+			downSQL = joinSQL(dropSQL, downSQL)
+		}
+	}
+
+	// Create migration file.
+	err = createMigration(viewMigrationPrefix("Create", v)+v.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// dropView creates a migration file to drop a view.
+func dropView(args []string, materialized bool) error {
+	// Caller should supply a view name as the first argument.
+	if len(args) < 1 {
+		return errors.New("requires a name argument")
+	}
+
+	// Set view data.
+	v := new(sqlt.View)
+	v.SetName(args[0])
+	v.SetMaterialized(materialized)
+
+	// Process SQL template for "up" migration.
+	upSQL, err := sqlt.ProcessTmpl(v, sqlt.DropViewTmpl)
+	if err != nil {
+		return err
+	}
+
+	// Restore the definition of the view in the "down" migration, if it can
+	// be found.
+	var downSQL string
+	prev, prevIndexes, err := previousView(v)
+	if err != nil {
+		return err
+	}
+	if prev != nil {
+		prevSQL, err := sqlt.ProcessTmpl(prev, sqlt.CreateViewTmpl)
+		if err != nil {
+			return err
+		}
+		downSQL = joinSQL(append([]string{prevSQL}, prevIndexes...)...)
+	}
+
+	// Create migration file.
+	err = createMigration(viewMigrationPrefix("Drop", v)+v.Name(), upSQL, downSQL)
+	if err != nil {
+		return err
+	}
+
+	return err
+}
+
+// viewQuery returns the query of a view from the --from-file flag or from the
+// arguments that follow the view name.
+func viewQuery(args []string) (string, error) {
+	if viewFromFile != "" {
+		return fileutil.ReadFileAsString(viewFromFile)
+	}
+	if len(args) < 2 {
+		return "", errors.New("requires a query argument or --from-file")
+	}
+
+	return strings.Join(args[1:], " "), nil
+}
+
+// viewIndexSQL returns CREATE INDEX statements for the --index and
+// --unique-index flags.
+func viewIndexSQL(v *sqlt.View) ([]string, error) {
+	if !v.Materialized() && len(viewIndexes)+len(viewUniqueIndexes) > 0 {
+		return nil, errors.New("only a materialized view can be indexed")
+	}
+
+	stmts := make([]string, 0)
+	for i, specs := range [][]string{viewIndexes, viewUniqueIndexes} {
+		for _, spec := range specs {
+			idx := new(sqlt.Index)
+			idx.SetTableName(v.Name())
+			idx.SetUnique(i == 1)
+			for _, c := range strings.Split(spec, ",") {
+				col, err := parseIndexColumn(strings.TrimSpace(c))
+				if err != nil {
+					return nil, err
+				}
+				idx.AddColumn(col)
+			}
+
+			s, err := sqlt.ProcessTmpl(idx, sqlt.CreateIndexTmpl)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, s)
+		}
+	}
+
+	return stmts, nil
+}
+
+// previousView returns the current definition of a view, and the CREATE INDEX
+// statements of a materialized view, before the migration that is being
+// generated. The definition is taken from the latest migration that created
+// the view or, if no migration created or dropped it, from the development
+// database. If the view does not exist, previousView returns nil.
+func previousView(v *sqlt.View) (*sqlt.View, []string, error) {
+	created, err := findLatestMigration(viewMigrationPrefix("Create", v) + v.Name())
+	if err != nil {
+		return nil, nil, err
+	}
+	dropped, err := findLatestMigration(viewMigrationPrefix("Drop", v) + v.Name())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if created != nil && (dropped == nil || created.Version() > dropped.Version()) {
+		return viewFromSQL(v, created.UpSQL())
+	}
+	if dropped != nil {
+		return nil, nil, nil
+	}
+
+	query, err := viewDefinitionFromDB(v)
+	if err != nil || query == "" {
+		return nil, nil, err
+	}
+	prev := new(sqlt.View)
+	prev.SetName(v.Name())
+	prev.SetMaterialized(v.Materialized())
+	prev.SetQuery(query)
+
+	return prev, nil, nil
+}
+
+// viewFromSQL recovers the definition of a view, and any CREATE INDEX
+// statements, from the "up" SQL of the migration that created it.
+func viewFromSQL(v *sqlt.View, sql string) (*sqlt.View, []string, error) {
+	var prev *sqlt.View
+	indexes := make([]string, 0)
+	for _, stmt := range migration.SplitStatements(sql) {
+		if m := viewStmtRegexp.FindStringSubmatch(stmt); m != nil && prev == nil {
+			prev = new(sqlt.View)
+			prev.SetName(v.Name())
+			prev.SetMaterialized(v.Materialized())
+			prev.SetQuery(m[1])
+		} else if indexStmtRegexp.MatchString(stmt) {
+			indexes = append(indexes, stmt)
+		}
+	}
+
+	if prev == nil {
+		return nil, nil, fmt.Errorf("could not find the definition of view %q in its latest migration", v.Name())
+	}
+
+	return prev, indexes, nil
+}
+
+// viewDefinitionFromDB queries the development database for the definition of
+// a view. If no database is configured or it cannot be reached, or the view
+// does not exist, viewDefinitionFromDB returns an empty string.
+func viewDefinitionFromDB(v *sqlt.View) (string, error) {
+	ctx := context.Background()
+	conn := connectDevelopmentDB(ctx)
+	if conn == nil {
+		return "", nil
+	}
+	defer conn.Close(ctx)
+
+	// Look up a schema-qualified view in its schema, and an unqualified view
+	// in the search path.
+	query := `SELECT definition FROM pg_views WHERE viewname = $1 AND schemaname = ANY (current_schemas(false));`
+	if v.Materialized() {
+		query = `SELECT definition FROM pg_matviews WHERE matviewname = $1 AND schemaname = ANY (current_schemas(false));`
+	}
+	schema, name := sqlt.SplitQualifiedName(v.Name())
+	args := []interface{}{name}
+	if schema != "" {
+		query = `SELECT definition FROM pg_views WHERE viewname = $1 AND schemaname = $2;`
+		if v.Materialized() {
+			query = `SELECT definition FROM pg_matviews WHERE matviewname = $1 AND schemaname = $2;`
+		}
+		args = append(args, schema)
+	}
+
+	var def string
+	err := conn.QueryRow(ctx, query, args...).Scan(&def)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+
+	return def, err
+}
+
+// viewMigrationPrefix returns the migration name prefix for an action on a view.
+func viewMigrationPrefix(action string, v *sqlt.View) string {
+	if v.Materialized() {
+		return action + "MaterializedView_"
+	}
+	return action + "View_"
+}
+
+// joinSQL joins SQL statements with blank lines.
+func joinSQL(stmts ...string) string {
+	return strings.Join(stmts, "\n\n")
+}
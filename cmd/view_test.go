@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+	"github.com/spf13/cobra"
+)
+
+// Unit test createViewMigration() and dropViewMigration()
+func TestCreateAndDropViewMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetViewFlagsHelper()
+
+	// Create the view with a query from a file.
+	fn := migrationsDir + "/active_users.sql"
+	err := fileutil.CreateAndWriteString(fn, "SELECT * FROM users WHERE active;\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	viewFromFile = fn
+	err = createViewMigration(cmd, []string{"ActiveUsers"})
+	os.Remove(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace the view, then drop it.
+	viewFromFile = ""
+	err = createViewMigration(cmd, []string{"ActiveUsers", "SELECT id, name FROM users WHERE active"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dropViewMigration(cmd, []string{"ActiveUsers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(3, t)
+
+	createSQL := "CREATE VIEW active_users AS\nSELECT * FROM users WHERE active;"
+	replaceSQL := "CREATE OR REPLACE VIEW active_users AS\nSELECT id, name FROM users WHERE active;"
+	restoreSQL := "CREATE OR REPLACE VIEW active_users AS\nSELECT * FROM users WHERE active;"
+	dropSQL := "DROP VIEW IF EXISTS active_users;"
+	recreateSQL := "CREATE VIEW active_users AS\nSELECT id, name FROM users WHERE active;"
+
+	cases := [][]string{
+		{createSQL, ms[0].UpSQL()},
+		{dropSQL, ms[0].DownSQL()},
+		{replaceSQL, ms[1].UpSQL()},
+		{restoreSQL, ms[1].DownSQL()},
+		{dropSQL, ms[2].UpSQL()},
+		{recreateSQL, ms[2].DownSQL()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("\nwant %q\n got %q\n", exp, act)
+		}
+	}
+
+	// A view that was dropped is not replaced.
+	err = createViewMigration(cmd, []string{"ActiveUsers", "SELECT 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms = readMigrationsHelper(4, t)
+	if exp, act := dropSQL, ms[3].DownSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+}
+
+// Unit test createMaterializedViewMigration()
+func TestCreateMaterializedViewMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetViewFlagsHelper()
+
+	// Create the view with indexes, then replace it.
+	viewWithNoData = true
+	viewUniqueIndexes = []string{"userID"}
+	viewIndexes = []string{"day,total:desc"}
+	err := createMaterializedViewMigration(cmd, []string{"DailyTotals", "SELECT user_id, day, sum(amount) AS total FROM orders GROUP BY 1, 2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resetViewFlagsHelper()
+	err = createMaterializedViewMigration(cmd, []string{"DailyTotals", "SELECT 1 AS total"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := readMigrationsHelper(2, t)
+
+	exp := `CREATE MATERIALIZED VIEW daily_totals AS
+SELECT user_id, day, sum(amount) AS total FROM orders GROUP BY 1, 2
+WITH NO DATA;
+
+CREATE INDEX daily_totals_day_total_mnrk_idx ON daily_totals (day, total DESC);
+
+CREATE UNIQUE INDEX daily_totals_user_id_mnrk_idx ON daily_totals (user_id);`
+	if act := ms[0].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+
+	exp = `DROP MATERIALIZED VIEW IF EXISTS daily_totals;
+
+CREATE MATERIALIZED VIEW daily_totals AS
+SELECT 1 AS total;`
+	if act := ms[1].UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+
+	// The down migration restores the previous definition and its indexes.
+	exp = `DROP MATERIALIZED VIEW IF EXISTS daily_totals;
+
+CREATE MATERIALIZED VIEW daily_totals AS
+SELECT user_id, day, sum(amount) AS total FROM orders GROUP BY 1, 2;
+
+CREATE INDEX daily_totals_day_total_mnrk_idx ON daily_totals (day, total DESC);
+
+CREATE UNIQUE INDEX daily_totals_user_id_mnrk_idx ON daily_totals (user_id);`
+	if act := ms[1].DownSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+
+	// Only a materialized view can be indexed.
+	viewIndexes = []string{"day"}
+	err = createViewMigration(cmd, []string{"DailyTotals", "SELECT 1"})
+	if err == nil {
+		t.Error("want error; got nil")
+	}
+}
+
+// Unit test refreshMaterializedViewMigration()
+func TestRefreshMaterializedViewMigration(t *testing.T) {
+	// Create a migrations directory.
+	cmd := &cobra.Command{}
+	mkdirMigrations(cmd, nil)
+	defer os.RemoveAll(migrationsDir) // Do cleanup
+	defer resetViewFlagsHelper()
+
+	viewConcurrently = true
+	err := refreshMaterializedViewMigration(cmd, []string{"DailyTotals"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := readMigrationsHelper(1, t)[0]
+
+	exp := "REFRESH MATERIALIZED VIEW CONCURRENTLY daily_totals;"
+	if act := m.UpSQL(); exp != act {
+		t.Errorf("\nwant %q\n got %q\n", exp, act)
+	}
+}
+
+// resetViewFlagsHelper resets the view options set by command flags.
+func resetViewFlagsHelper() {
+	viewFromFile = ""
+	viewWithNoData = false
+	viewIndexes = nil
+	viewUniqueIndexes = nil
+	viewConcurrently = false
+}
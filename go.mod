@@ -4,9 +4,11 @@ go 1.14
 
 require (
 	github.com/iancoleman/strcase v0.0.0-20191112232945-16388991a334
+	github.com/jackc/pgconn v1.5.0
 	github.com/jackc/pgtype v1.3.0
 	github.com/jackc/pgx/v4 v4.6.0
 	github.com/lib/pq v1.5.2 // indirect
 	github.com/spf13/cobra v1.0.0
+	github.com/spf13/pflag v1.0.3
 	github.com/spf13/viper v1.7.0
 )
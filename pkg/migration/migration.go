@@ -1,6 +1,8 @@
 package migration
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
@@ -14,6 +16,27 @@ import (
 const (
 	// Delimiter used for parsing migration files.
 	migrationDelimiter string = "-- MIGRATION DELIMITER (DO NOT DELETE THIS COMMENT) --"
+
+	// Prefix of directive comments at the top of a migration file.
+	directivePrefix string = "-- monarch:"
+
+	// Prefix of the file name of a repeatable migration.
+	repeatablePrefix string = "R_"
+)
+
+// Transaction modes that control how a migration is executed.
+const (
+	// TransactionDefault executes the migration in a transaction shared with
+	// adjacent migrations.
+	TransactionDefault string = ""
+
+	// TransactionIsolated executes the migration in its own transaction.
+	TransactionIsolated string = "isolated"
+
+	// TransactionNone executes the migration outside of a transaction, one
+	// statement at a time. It is required by statements such as CREATE INDEX
+	// CONCURRENTLY that cannot run inside a transaction block.
+	TransactionNone string = "none"
 )
 
 // Migration ...
@@ -24,6 +47,9 @@ type Migration struct {
 	downSQL        string
 	sql            string
 	version        int64
+	transaction    string
+	repeatable     bool
+	template       bool
 }
 
 // Name returns the migration name.
@@ -31,9 +57,11 @@ func (m *Migration) Name() string {
 	return m.name
 }
 
-// SetName sets migration name after converting name string to snake_case.
+// SetName sets migration name after converting name string to snake_case. The
+// dot of a schema-qualified name is converted to an underscore, so that it
+// does not end up in the migration file name.
 func (m *Migration) SetName(name string) {
-	m.name = strcase.ToSnake(name)
+	m.name = strcase.ToSnake(strings.ReplaceAll(name, ".", "_"))
 }
 
 // LeadingComment returns the migration leading comment
@@ -72,11 +100,64 @@ func (m *Migration) SetDownSQL(sql string) {
 	m.downSQL = sql
 }
 
-// SQL returns the migration SQL including leading comment, up SQL and down SQL.
+// Transaction returns the transaction mode.
+func (m *Migration) Transaction() string {
+	return m.transaction
+}
+
+// SetTransaction sets the transaction mode.
+func (m *Migration) SetTransaction(mode string) {
+	m.transaction = mode
+}
+
+// Template reports whether the migration SQL is a template whose placeholders
+// are resolved when the migration is executed.
+func (m *Migration) Template() bool {
+	return m.template
+}
+
+// SetTemplate ...
+func (m *Migration) SetTemplate(template bool) {
+	m.template = template
+}
+
+// Repeatable reports whether the migration is a repeatable migration, which
+// has no version and is executed again whenever its checksum changes.
+func (m *Migration) Repeatable() bool {
+	return m.repeatable
+}
+
+// SetRepeatable ...
+func (m *Migration) SetRepeatable(repeatable bool) {
+	m.repeatable = repeatable
+}
+
+// Checksum returns the hex-encoded SHA-256 hash of the "up" SQL.
+func (m *Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.upSQL))
+	return hex.EncodeToString(sum[:])
+}
+
+// Key returns the name of the migration file without its extension, e.g.
+// "1612345678_create_table_users" or "R_create_view_active_users".
+func (m *Migration) Key() string {
+	if m.repeatable {
+		return repeatablePrefix + m.name
+	}
+	return fmt.Sprintf("%d_%s", m.version, m.name)
+}
+
+// SQL returns the migration SQL including directives, up SQL and down SQL.
 func (m *Migration) SQL() string {
 	var sql string
 
 	substr := make([]string, 0)
+	if m.transaction != TransactionDefault {
+		substr = append(substr, directivePrefix+"transaction "+m.transaction)
+	}
+	if m.template {
+		substr = append(substr, directivePrefix+"template")
+	}
 	substr = append(substr, m.upSQL)
 	substr = append(substr, migrationDelimiter)
 	substr = append(substr, m.downSQL)
@@ -99,21 +180,37 @@ func (m *Migration) SetVersion(ver int64) {
 // ReadFromFile creates a migration file in the directory specified by "dir"
 // and writes content to it based on this migration's fields.
 func (m *Migration) ReadFromFile(path string) error {
-	// Set name
-	name := extractNameFromFile(path)
-	m.SetName(name)
-
-	// Set version
-	version, err := extractVersionFromFile(path)
-	if err != nil {
-		return err
+	// Set name and version. A repeatable migration has no version.
+	m.SetRepeatable(isRepeatableFile(path))
+	if m.Repeatable() {
+		m.SetName(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), repeatablePrefix), ".sql"))
+		m.SetVersion(0)
+	} else {
+		m.SetName(extractNameFromFile(path))
+		version, err := extractVersionFromFile(path)
+		if err != nil {
+			return err
+		}
+		m.SetVersion(version)
 	}
-	m.SetVersion(version)
 
 	// Set upSQL and downSQL
 	str, err := fileutil.ReadFileAsString(path)
+	if err != nil {
+		return err
+	}
 	parts := strings.Split(str, migrationDelimiter)
-	upSQL := strings.TrimSpace(parts[0])
+	if len(parts) < 2 && m.Repeatable() {
+		// A repeatable migration has no use for "down" SQL.
+		parts = append(parts, "")
+	}
+	if len(parts) < 2 {
+		return fmt.Errorf("migration file %q is missing the migration delimiter", path)
+	}
+	upSQL, err := m.readDirectives(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return fmt.Errorf("migration file %q: %s", path, err)
+	}
 	downSQL := strings.TrimSpace(parts[1])
 	m.SetUpSQL(upSQL)
 	m.SetDownSQL(downSQL)
@@ -121,12 +218,52 @@ func (m *Migration) ReadFromFile(path string) error {
 	return err
 }
 
+// readDirectives consumes the directive comments at the top of the "up"
+// section of a migration file, applies them to the migration and returns
+// the remaining SQL.
+func (m *Migration) readDirectives(sql string) (string, error) {
+	m.SetTransaction(TransactionDefault)
+	m.SetTemplate(false)
+
+	for strings.HasPrefix(sql, directivePrefix) {
+		// Split off the directive line.
+		line := sql
+		rest := ""
+		if i := strings.Index(sql, "\n"); i >= 0 {
+			line, rest = sql[:i], sql[i+1:]
+		}
+		sql = strings.TrimSpace(rest)
+
+		// Apply the directive.
+		fields := strings.Fields(strings.TrimPrefix(line, directivePrefix))
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "transaction":
+			if len(fields) != 2 || (fields[1] != TransactionIsolated && fields[1] != TransactionNone) {
+				return sql, fmt.Errorf("invalid transaction directive %q", line)
+			}
+			m.SetTransaction(fields[1])
+		case "template":
+			if len(fields) != 1 {
+				return sql, fmt.Errorf("invalid template directive %q", line)
+			}
+			m.SetTemplate(true)
+		default:
+			return sql, fmt.Errorf("unknown directive %q", line)
+		}
+	}
+
+	return sql, nil
+}
+
 // WriteToFile creates a migration file in the directory specified by "dir"
 // and writes content to it based on this migration's fields.
 func (m *Migration) WriteToFile(dirname string) (string, error) {
 
 	// Generate migration file name.
-	fn := fmt.Sprintf("%s/%d_%s.sql", dirname, m.Version(), m.Name())
+	fn := fmt.Sprintf("%s/%s.sql", dirname, m.Key())
 
 	// Create migration file.
 	err := fileutil.CreateAndWriteString(fn, m.SQL())
@@ -137,19 +274,35 @@ func (m *Migration) WriteToFile(dirname string) (string, error) {
 	return fn, err
 }
 
-// LoadAllLaterThan ...
-func LoadAllLaterThan(version int64, dirname string) ([]Migration, error) {
+// LoadPending reads in the migrations in the directory specified by "dirname"
+// that may need to be executed: the versioned migrations with a version
+// greater than "version", in order of version, followed by all repeatable
+// migrations, in order of name. The caller decides which repeatable
+// migrations to execute by comparing their checksums with those recorded
+// when they were last executed.
+func LoadPending(version int64, dirname string) ([]Migration, error) {
 	migrations := make([]Migration, 0)
+	repeatables := make([]Migration, 0)
 
-	// Get the list of files in the directory specificed by path.
+	// Get the list of files in the directory specificed by path. The list is
+	// sorted by file name, and so by version and by name.
 	files, err := ioutil.ReadDir(dirname)
 	if err != nil {
 		return migrations, err
 	}
 
-	var m Migration
 	for _, f := range files {
 		n := f.Name()
+		if isRepeatableFile(n) {
+			var m Migration
+			err = m.ReadFromFile(dirname + "/" + n)
+			if err != nil {
+				return migrations, err
+			}
+			repeatables = append(repeatables, m)
+			continue
+		}
+
 		v, err := extractVersionFromFile(n)
 		if err != nil {
 			return migrations, err
@@ -158,16 +311,21 @@ func LoadAllLaterThan(version int64, dirname string) ([]Migration, error) {
 		// Select only the migration files with a version greater than
 		// schemaVersion
 		if v > version {
+			var m Migration
 			err = m.ReadFromFile(dirname + "/" + n)
 			if err != nil {
 				return migrations, err
 			}
 			migrations = append(migrations, m)
-			fmt.Printf("Staged %q migration version: %d\n", "up", m.Version())
 		}
 	}
 
-	return migrations, err
+	return append(migrations, repeatables...), err
+}
+
+// isRepeatableFile reports whether path names a repeatable migration file.
+func isRepeatableFile(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), repeatablePrefix)
 }
 
 // extractNameFromFile extracts name from a migration filename.
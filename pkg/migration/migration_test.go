@@ -40,6 +40,17 @@ func TestMigrationName(t *testing.T) {
 	}
 }
 
+// Unit test Migration.SetName with a schema-qualified name
+func TestMigrationQualifiedName(t *testing.T) {
+	m := Migration{}
+	exp := "create_table_billing_invoices"
+
+	m.SetName("CreateTable_billing.invoices")
+	if act := m.Name(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
 // Unit test Migration.LeadingComment
 func TestMigrationLeadingComment(t *testing.T) {
 	m := Migration{}
@@ -167,7 +178,117 @@ func TestMigrationReadFromFile(t *testing.T) {
 	}
 }
 
-func TestLoadAllLaterThan(t *testing.T) {
+// Unit test Migration.SQL() with a transaction directive.
+func TestMigrationSQLWithTransaction(t *testing.T) {
+	m := Migration{}
+	m.SetUpSQL("ALTER TABLE cars VALIDATE CONSTRAINT cars_people_mnrk_fkc;")
+	m.SetTransaction(TransactionIsolated)
+
+	exp := `-- monarch:transaction isolated
+
+ALTER TABLE cars VALIDATE CONSTRAINT cars_people_mnrk_fkc;
+
+-- MIGRATION DELIMITER (DO NOT DELETE THIS COMMENT) --
+
+`
+
+	act := m.SQL()
+	if exp != act {
+		t.Errorf("want %q\n; got %q\n", exp, act)
+	}
+}
+
+// Unit test Migration.ReadFromFile() with a transaction directive.
+func TestMigrationReadFromFileWithTransaction(t *testing.T) {
+	m := Migration{}
+	m.SetName("ValidateForeignKeyOn_cars")
+	m.SetUpSQL("ALTER TABLE cars VALIDATE CONSTRAINT cars_people_mnrk_fkc;")
+	m.SetVersion(time.Now().UnixNano())
+	m.SetTransaction(TransactionIsolated)
+	fn, err := m.WriteToFile(tmpTestMigrationsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(fn)
+
+	// Allocate a new migration and read in from file.
+	rm := new(Migration)
+	err = rm.ReadFromFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify transaction mode
+	exp := TransactionIsolated
+	act := rm.Transaction()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	// Verify that the directive is not part of upSQL
+	exp = m.UpSQL()
+	act = rm.UpSQL()
+	if exp != act {
+		t.Errorf("want %q\n; got %q\n", exp, act)
+	}
+}
+
+// Unit test Migration.ReadFromFile() with a template directive.
+func TestMigrationReadFromFileWithTemplate(t *testing.T) {
+	m := Migration{}
+	m.SetName("GrantOnTable_users")
+	m.SetUpSQL("GRANT SELECT ON users TO {{.reader}};")
+	m.SetVersion(time.Now().UnixNano())
+	m.SetTransaction(TransactionIsolated)
+	m.SetTemplate(true)
+	fn, err := m.WriteToFile(tmpTestMigrationsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(fn)
+
+	// Allocate a new migration and read in from file.
+	rm := new(Migration)
+	err = rm.ReadFromFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !rm.Template() || rm.Transaction() != TransactionIsolated {
+		t.Errorf("want an isolated template migration; got template %t, transaction %q", rm.Template(), rm.Transaction())
+	}
+	if exp, act := m.UpSQL(), rm.UpSQL(); exp != act {
+		t.Errorf("want %q\n; got %q\n", exp, act)
+	}
+}
+
+// Unit test Migration.ReadFromFile() with an invalid directive.
+func TestMigrationReadFromFileInvalidDirective(t *testing.T) {
+	fn := tmpTestMigrationsDir + "1_invalid_directive.sql"
+	sql := "-- monarch:transaction sometimes\n\nSELECT 1;\n\n" + migrationDelimiter + "\n\n"
+	err := fileutil.CreateAndWriteString(fn, sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(fn)
+
+	m := new(Migration)
+	err = m.ReadFromFile(fn)
+	if err == nil {
+		t.Error("want error; got nil")
+	}
+}
+
+// Unit test LoadPending()
+func TestLoadPending(t *testing.T) {
+	// A repeatable migration, which is loaded regardless of version.
+	fn := tmpTestMigrationsDir + "R_create_view_active.sql"
+	err := fileutil.CreateAndWriteString(fn, "CREATE OR REPLACE VIEW active AS SELECT 1;\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(fn)
+
 	// Migration one
 	makeMigrationHelper("one", t)
 
@@ -178,15 +299,22 @@ func TestLoadAllLaterThan(t *testing.T) {
 	makeMigrationHelper("two", t)
 	makeMigrationHelper("three", t)
 
-	// Run LoadAllLaterThan() - later than version
-	migrations, err := LoadAllLaterThan(version, tmpTestMigrationsDir)
+	// Run LoadPending() - later than version
+	migrations, err := LoadPending(version, tmpTestMigrationsDir)
 	if err != nil {
 		t.Error(err)
 	}
 
-	// Should have loaded exactly two migrations (the latter two).
-	if count := len(migrations); count != 2 {
-		t.Errorf("want 2; got %d", count)
+	// Should have loaded exactly three migrations (the latter two, then the
+	// repeatable migration).
+	if count := len(migrations); count != 3 {
+		t.Fatalf("want 3; got %d", count)
+	}
+	if exp, act := "R_create_view_active", migrations[2].Key(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+	if exp, act := "CREATE OR REPLACE VIEW active AS SELECT 1;", migrations[2].UpSQL(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
 	}
 
 	// Verifiy migration 2 version is greater than "version" timestamp
@@ -246,6 +374,28 @@ func TestLoadAllLaterThan(t *testing.T) {
 	}
 }
 
+// Unit test Migration.Key() and Migration.Checksum()
+func TestMigrationKey(t *testing.T) {
+	m := Migration{}
+	m.SetName("CreateViewActive")
+	m.SetVersion(42)
+	m.SetUpSQL("CREATE OR REPLACE VIEW active AS SELECT 1;")
+
+	if exp, act := "42_create_view_active", m.Key(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+	m.SetRepeatable(true)
+	if exp, act := "R_create_view_active", m.Key(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	checksum := m.Checksum()
+	m.SetUpSQL("CREATE OR REPLACE VIEW active AS SELECT 2;")
+	if checksum == m.Checksum() {
+		t.Errorf("want a different checksum; got %q", checksum)
+	}
+}
+
 // makeMigrationHelper creates a migration to create a table with name "tn"
 // the writes it to a file by calling WriteToFile().
 func makeMigrationHelper(tn string, t *testing.T) (Migration, string) {
@@ -0,0 +1,115 @@
+package migration
+
+import (
+	"strings"
+)
+
+// SplitStatements splits a string of SQL into individual statements at each
+// semicolon that is not inside a string literal, quoted identifier,
+// dollar-quoted string or comment. Statements are trimmed of surrounding
+// whitespace and empty statements are omitted.
+func SplitStatements(sql string) []string {
+	stmts := make([]string, 0)
+	start := 0
+
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\'':
+			// String literal; an E'' string may contain backslash escapes.
+			escapes := i > 0 && (sql[i-1] == 'E' || sql[i-1] == 'e')
+			i = skipQuoted(sql, i, '\'', escapes)
+		case c == '"':
+			// Quoted identifier.
+			i = skipQuoted(sql, i, '"', false)
+		case c == '$':
+			// Dollar-quoted string, e.g. $$...$$ or $body$...$body$.
+			if tag, ok := dollarTag(sql[i:]); ok {
+				end := strings.Index(sql[i+len(tag):], tag)
+				if end < 0 {
+					i = len(sql)
+				} else {
+					i += len(tag) + end + len(tag) - 1
+				}
+			}
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			// Line comment.
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			// Block comment; block comments may be nested.
+			i = skipBlockComment(sql, i)
+		case c == ';':
+			stmts = appendStatement(stmts, sql[start:i+1])
+			start = i + 1
+		}
+	}
+	stmts = appendStatement(stmts, sql[start:])
+
+	return stmts
+}
+
+// appendStatement appends a trimmed statement to stmts unless it is empty.
+func appendStatement(stmts []string, stmt string) []string {
+	stmt = strings.TrimSpace(stmt)
+	if stmt == "" || stmt == ";" {
+		return stmts
+	}
+	return append(stmts, stmt)
+}
+
+// skipQuoted returns the index of the quote that closes the quoted text that
+// starts at index i. A doubled quote is an escaped quote.
+func skipQuoted(sql string, i int, quote byte, escapes bool) int {
+	for j := i + 1; j < len(sql); j++ {
+		switch {
+		case escapes && sql[j] == '\\':
+			j++
+		case sql[j] == quote && j+1 < len(sql) && sql[j+1] == quote:
+			j++
+		case sql[j] == quote:
+			return j
+		}
+	}
+	return len(sql)
+}
+
+// skipBlockComment returns the index of the last character of the block
+// comment that starts at index i.
+func skipBlockComment(sql string, i int) int {
+	depth := 0
+	for j := i; j < len(sql)-1; j++ {
+		switch {
+		case sql[j] == '/' && sql[j+1] == '*':
+			depth++
+			j++
+		case sql[j] == '*' && sql[j+1] == '/':
+			depth--
+			j++
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(sql)
+}
+
+// dollarTag returns the dollar-quote tag, e.g. "$$" or "$body$", at the start
+// of s. A positional parameter such as $1 is not a tag.
+func dollarTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		switch {
+		case c == '$':
+			return s[:j+1], true
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80:
+		case c >= '0' && c <= '9' && j > 1:
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,46 @@
+package migration
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Unit test SplitStatements()
+func TestSplitStatements(t *testing.T) {
+	cases := []struct {
+		sql string
+		exp []string
+	}{
+		{
+			"CREATE INDEX CONCURRENTLY a ON t (x);\n\nCREATE INDEX CONCURRENTLY b ON t (y);",
+			[]string{"CREATE INDEX CONCURRENTLY a ON t (x);", "CREATE INDEX CONCURRENTLY b ON t (y);"},
+		},
+		{
+			"INSERT INTO t VALUES ('a;b', 'it''s; here', E'\\'; x');",
+			[]string{"INSERT INTO t VALUES ('a;b', 'it''s; here', E'\\'; x');"},
+		},
+		{
+			`SELECT 1 AS "semi;colon"; SELECT 2`,
+			[]string{`SELECT 1 AS "semi;colon";`, "SELECT 2"},
+		},
+		{
+			"CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql;\nSELECT $1;",
+			[]string{"CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql;", "SELECT $1;"},
+		},
+		{
+			"-- a comment; with a semicolon\nSELECT 1; /* block; /* nested; */ comment */ SELECT 2;",
+			[]string{"-- a comment; with a semicolon\nSELECT 1;", "/* block; /* nested; */ comment */ SELECT 2;"},
+		},
+		{
+			" ;\n\n ",
+			[]string{},
+		},
+	}
+
+	for _, c := range cases {
+		act := SplitStatements(c.sql)
+		if !reflect.DeepEqual(c.exp, act) {
+			t.Errorf("\nwant %q\n got %q\n", c.exp, act)
+		}
+	}
+}
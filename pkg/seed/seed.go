@@ -0,0 +1,166 @@
+package seed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+)
+
+// Prefix of the file name of a repeatable seed.
+const repeatablePrefix string = "R_"
+
+// Seed is an SQL file that loads data into a database. A versioned seed, in a
+// file named "<version>_<name>.sql", is executed once. A repeatable seed, in a
+// file named "R_<name>.sql", is executed again whenever its checksum changes.
+type Seed struct {
+	name       string
+	version    int64
+	repeatable bool
+	sql        string
+}
+
+// Name returns the seed name.
+func (s *Seed) Name() string {
+	return s.name
+}
+
+// SetName sets the seed name after converting it to snake_case.
+func (s *Seed) SetName(name string) {
+	s.name = strcase.ToSnake(name)
+}
+
+// Version returns the version that orders a versioned seed.
+func (s *Seed) Version() int64 {
+	return s.version
+}
+
+// SetVersion sets version.
+func (s *Seed) SetVersion(ver int64) {
+	s.version = ver
+}
+
+// Repeatable reports whether the seed is executed again when its checksum
+// changes.
+func (s *Seed) Repeatable() bool {
+	return s.repeatable
+}
+
+// SetRepeatable ...
+func (s *Seed) SetRepeatable(repeatable bool) {
+	s.repeatable = repeatable
+}
+
+// SQL returns the seed SQL.
+func (s *Seed) SQL() string {
+	return s.sql
+}
+
+// SetSQL sets the seed SQL.
+func (s *Seed) SetSQL(sql string) {
+	s.sql = sql
+}
+
+// Checksum returns the hex-encoded SHA-256 hash of the seed SQL.
+func (s *Seed) Checksum() string {
+	sum := sha256.Sum256([]byte(s.sql))
+	return hex.EncodeToString(sum[:])
+}
+
+// Key returns the name of the seed file without its extension, which
+// identifies the seed in the table that records executed seeds.
+func (s *Seed) Key() string {
+	if s.repeatable {
+		return repeatablePrefix + s.name
+	}
+	return fmt.Sprintf("%d_%s", s.version, s.name)
+}
+
+// FileName returns the name of the seed file.
+func (s *Seed) FileName() string {
+	return s.Key() + ".sql"
+}
+
+// ReadFromFile reads a seed from the file at path.
+func (s *Seed) ReadFromFile(path string) error {
+	fn := strings.TrimSuffix(filepath.Base(path), ".sql")
+	if strings.HasPrefix(fn, repeatablePrefix) {
+		s.SetRepeatable(true)
+		s.SetVersion(0)
+		s.SetName(strings.TrimPrefix(fn, repeatablePrefix))
+	} else {
+		parts := strings.SplitN(fn, "_", 2)
+		ver, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || len(parts) < 2 {
+			return fmt.Errorf("seed file %q is not named <version>_<name>.sql or %s<name>.sql", path, repeatablePrefix)
+		}
+		s.SetRepeatable(false)
+		s.SetVersion(ver)
+		s.SetName(parts[1])
+	}
+
+	sql, err := fileutil.ReadFileAsString(path)
+	if err != nil {
+		return err
+	}
+	s.SetSQL(sql)
+
+	return err
+}
+
+// WriteToFile creates a seed file in the directory specified by "dirname" and
+// writes the seed SQL to it.
+func (s *Seed) WriteToFile(dirname string) (string, error) {
+	fn := dirname + "/" + s.FileName()
+
+	err := fileutil.CreateAndWriteString(fn, s.sql)
+	if err != nil {
+		return fn, err
+	}
+
+	return fn, err
+}
+
+// LoadAll reads in the seed files in the directory specified by "dirname" in
+// the order in which they are executed: versioned seeds by version, then
+// repeatable seeds by name. Files without the .sql extension are ignored.
+func LoadAll(dirname string) ([]Seed, error) {
+	seeds := make([]Seed, 0)
+
+	files, err := ioutil.ReadDir(dirname)
+	if err != nil {
+		return seeds, err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".sql") {
+			continue
+		}
+		var s Seed
+		err = s.ReadFromFile(dirname + "/" + f.Name())
+		if err != nil {
+			return seeds, err
+		}
+		seeds = append(seeds, s)
+	}
+
+	sort.SliceStable(seeds, func(i, j int) bool {
+		a, b := seeds[i], seeds[j]
+		if a.repeatable != b.repeatable {
+			return !a.repeatable
+		}
+		if a.repeatable {
+			return a.name < b.name
+		}
+		return a.version < b.version
+	})
+
+	return seeds, err
+}
@@ -0,0 +1,107 @@
+package seed
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+)
+
+const (
+	tmpDir          string = "tmp"
+	tmpTestSeedsDir string = "tmp/test/seeds"
+)
+
+func TestMain(m *testing.M) {
+	// Setup
+	fileutil.MkdirP(tmpTestSeedsDir)
+
+	// Execute tests.
+	i := m.Run()
+
+	// Teardown
+	os.RemoveAll(tmpDir) // Do cleanup
+
+	// Exit
+	os.Exit(i)
+}
+
+// Unit test Seed.Key() and Seed.FileName()
+func TestSeedFileName(t *testing.T) {
+	s := Seed{}
+	s.SetName("Countries")
+	s.SetVersion(42)
+
+	exp := "42_countries.sql"
+	act := s.FileName()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	s.SetRepeatable(true)
+	exp = "R_countries"
+	act = s.Key()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test Seed.Checksum()
+func TestSeedChecksum(t *testing.T) {
+	a := Seed{sql: "INSERT INTO roles (name) VALUES ('admin');"}
+	b := Seed{sql: "INSERT INTO roles (name) VALUES ('member');"}
+
+	if a.Checksum() == b.Checksum() {
+		t.Errorf("want different checksums; got %q", a.Checksum())
+	}
+	if exp, act := 64, len(a.Checksum()); exp != act {
+		t.Errorf("want %d; got %d", exp, act)
+	}
+}
+
+// Unit test Seed.ReadFromFile() with a badly named file
+func TestSeedReadFromFileInvalidName(t *testing.T) {
+	s := Seed{}
+	err := s.ReadFromFile(tmpTestSeedsDir + "/countries.sql")
+	if err == nil {
+		t.Errorf("want error; got nil")
+	}
+}
+
+// Unit test WriteToFile() and LoadAll()
+func TestLoadAll(t *testing.T) {
+	seeds := []Seed{
+		{name: "statuses", repeatable: true, sql: "SELECT 3;"},
+		{name: "users", version: 20, sql: "SELECT 2;"},
+		{name: "countries", repeatable: true, sql: "SELECT 4;"},
+		{name: "roles", version: 10, sql: "SELECT 1;"},
+	}
+	for _, s := range seeds {
+		_, err := s.WriteToFile(tmpTestSeedsDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := fileutil.CreateAndWriteString(tmpTestSeedsDir+"/README.md", "Not a seed.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadAll(tmpTestSeedsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{"10_roles", "20_users", "R_countries", "R_statuses"}
+	if len(loaded) != len(exp) {
+		t.Fatalf("want %d seeds; got %d", len(exp), len(loaded))
+	}
+	for i, s := range loaded {
+		if exp[i] != s.Key() {
+			t.Errorf("want %q; got %q", exp[i], s.Key())
+		}
+	}
+	if exp, act := "SELECT 1;", loaded[0].SQL(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
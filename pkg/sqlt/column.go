@@ -9,6 +9,7 @@ type Column struct {
 	name    string
 	newName string
 	colType string
+	comment string
 }
 
 // Name ...
@@ -42,3 +43,22 @@ func (c *Column) SetType(name string) {
 	// c.colType = strings.ToLower(name)
 	c.colType = name
 }
+
+// Comment returns the comment on the column.
+func (c *Column) Comment() string {
+	return c.comment
+}
+
+// SetComment ...
+func (c *Column) SetComment(comment string) {
+	c.comment = comment
+}
+
+// QuotedComment returns the comment on the column as a string literal, or an
+// empty string if the column has no comment.
+func (c *Column) QuotedComment() string {
+	if c.comment == "" {
+		return ""
+	}
+	return quoteLiteral(c.comment)
+}
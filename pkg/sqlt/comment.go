@@ -0,0 +1,61 @@
+package sqlt
+
+import (
+	"github.com/iancoleman/strcase"
+)
+
+// Comment is a comment on a table or on a column of a table.
+type Comment struct {
+	tableName  string
+	columnName string
+	text       string
+}
+
+// TableName ...
+func (c *Comment) TableName() string {
+	return c.tableName
+}
+
+// SetTableName ...
+func (c *Comment) SetTableName(name string) {
+	c.tableName = qualifiedName(name)
+}
+
+// ColumnName returns the name of the column. An empty string means that the
+// comment is on the table.
+func (c *Comment) ColumnName() string {
+	return c.columnName
+}
+
+// SetColumnName ...
+func (c *Comment) SetColumnName(name string) {
+	c.columnName = strcase.ToSnake(name)
+}
+
+// Object returns the commented object formatted for a COMMENT statement,
+// e.g. `TABLE users` or `COLUMN users.email`.
+func (c *Comment) Object() string {
+	if c.columnName != "" {
+		return "COLUMN " + c.tableName + "." + c.columnName
+	}
+	return "TABLE " + c.tableName
+}
+
+// Text returns the text of the comment.
+func (c *Comment) Text() string {
+	return c.text
+}
+
+// SetText sets the text of the comment. An empty text removes the comment.
+func (c *Comment) SetText(text string) {
+	c.text = text
+}
+
+// Literal returns the text of the comment as a string literal, or NULL if
+// the comment is removed.
+func (c *Comment) Literal() string {
+	if c.text == "" {
+		return "NULL"
+	}
+	return quoteLiteral(c.text)
+}
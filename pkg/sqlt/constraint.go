@@ -0,0 +1,197 @@
+package sqlt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Constraint kinds supported by Constraint.
+const (
+	CheckConstraint   string = "check"
+	UniqueConstraint  string = "unique"
+	ExcludeConstraint string = "exclude"
+)
+
+// Constraint ...
+type Constraint struct {
+	name       string
+	kind       string
+	tableName  string
+	expression string
+	columns    []string
+	elements   []ExcludeElement
+	method     string
+	notValid   bool
+}
+
+// ExcludeElement is a column and operator pair used by an EXCLUDE constraint.
+type ExcludeElement struct {
+	column   string
+	operator string
+}
+
+// Column ...
+func (e *ExcludeElement) Column() string {
+	return e.column
+}
+
+// SetColumn ...
+func (e *ExcludeElement) SetColumn(name string) {
+	e.column = strcase.ToSnake(name)
+}
+
+// Operator ...
+func (e *ExcludeElement) Operator() string {
+	return e.operator
+}
+
+// SetOperator ...
+func (e *ExcludeElement) SetOperator(op string) {
+	e.operator = op
+}
+
+// Name ...
+func (c *Constraint) Name() string {
+	return c.name
+}
+
+// SetName ...
+func (c *Constraint) SetName(name string) {
+	c.name = strcase.ToSnake(name)
+}
+
+// ConstraintName will generate a name from TableName and either Name (CHECK)
+// or the constrained columns (UNIQUE, EXCLUDE) followed by a `_mnrk_` suffix,
+// which is truncated to fit PostgreSQL's 63-byte identifier limit.
+func (c *Constraint) ConstraintName() string {
+	label := c.Name()
+	if label == "" {
+		label = strings.Join(c.columnNames(), "_")
+	}
+
+	base := fmt.Sprintf("%s_%s", unqualifiedName(c.TableName()), label)
+	return identifier(base, "_mnrk_"+c.suffix())
+}
+
+// Kind ...
+func (c *Constraint) Kind() string {
+	return c.kind
+}
+
+// SetKind ...
+func (c *Constraint) SetKind(kind string) {
+	c.kind = strings.ToLower(kind)
+}
+
+// TableName ...
+func (c *Constraint) TableName() string {
+	return c.tableName
+}
+
+// SetTableName ...
+func (c *Constraint) SetTableName(name string) {
+	c.tableName = qualifiedName(name)
+}
+
+// Expression returns the boolean expression of a CHECK constraint.
+func (c *Constraint) Expression() string {
+	return c.expression
+}
+
+// SetExpression sets the boolean expression of a CHECK constraint.
+func (c *Constraint) SetExpression(expr string) {
+	c.expression = expr
+}
+
+// Columns returns the columns of a UNIQUE constraint.
+func (c *Constraint) Columns() []string {
+	return c.columns
+}
+
+// AddColumn adds a column to a UNIQUE constraint.
+func (c *Constraint) AddColumn(name string) {
+	c.columns = append(c.columns, strcase.ToSnake(name))
+}
+
+// Elements returns the elements of an EXCLUDE constraint.
+func (c *Constraint) Elements() []ExcludeElement {
+	return c.elements
+}
+
+// AddElement adds an element to an EXCLUDE constraint.
+func (c *Constraint) AddElement(e ExcludeElement) {
+	c.elements = append(c.elements, e)
+}
+
+// Method returns the index access method of an EXCLUDE constraint. The
+// default is gist.
+func (c *Constraint) Method() string {
+	if c.method == "" {
+		return "gist"
+	}
+	return c.method
+}
+
+// SetMethod ...
+func (c *Constraint) SetMethod(method string) {
+	c.method = strings.ToLower(method)
+}
+
+// NotValid reports whether the constraint is added as NOT VALID, i.e. without
+// checking existing rows.
+func (c *Constraint) NotValid() bool {
+	return c.notValid
+}
+
+// SetNotValid ...
+func (c *Constraint) SetNotValid(notValid bool) {
+	c.notValid = notValid
+}
+
+// Definition returns the constraint clause that follows the constraint name
+// in an ADD CONSTRAINT statement.
+func (c *Constraint) Definition() string {
+	switch c.Kind() {
+	case CheckConstraint:
+		return fmt.Sprintf("CHECK (%s)", c.Expression())
+	case UniqueConstraint:
+		return fmt.Sprintf("UNIQUE (%s)", strings.Join(c.Columns(), ", "))
+	case ExcludeConstraint:
+		elems := make([]string, 0, len(c.elements))
+		for _, e := range c.elements {
+			elems = append(elems, fmt.Sprintf("%s WITH %s", e.Column(), e.Operator()))
+		}
+		return fmt.Sprintf("EXCLUDE USING %s (%s)", c.Method(), strings.Join(elems, ", "))
+	}
+
+	return ""
+}
+
+// columnNames returns the names of the constrained columns.
+func (c *Constraint) columnNames() []string {
+	if c.Kind() == ExcludeConstraint {
+		names := make([]string, 0, len(c.elements))
+		for _, e := range c.elements {
+			names = append(names, e.Column())
+		}
+		return names
+	}
+
+	return c.Columns()
+}
+
+// suffix returns the constraint name suffix for the constraint kind.
+func (c *Constraint) suffix() string {
+	switch c.Kind() {
+	case CheckConstraint:
+		return "ckc"
+	case UniqueConstraint:
+		return "uqc"
+	case ExcludeConstraint:
+		return "exc"
+	}
+
+	return "c"
+}
@@ -0,0 +1,80 @@
+package sqlt
+
+import (
+	"testing"
+)
+
+// Unit test Constraint.ConstraintName()
+func TestConstraintConstraintName(t *testing.T) {
+	chk := Constraint{}
+	chk.SetTableName("Users")
+	chk.SetKind("CHECK")
+	chk.SetName("adultAge")
+
+	uq := Constraint{}
+	uq.SetTableName("users")
+	uq.SetKind(UniqueConstraint)
+	uq.AddColumn("accountID")
+	uq.AddColumn("email")
+
+	ex := Constraint{}
+	ex.SetTableName("bookings")
+	ex.SetKind(ExcludeConstraint)
+	ex.AddElement(ExcludeElement{"room_id", "="})
+	ex.AddElement(ExcludeElement{"during", "&&"})
+
+	cases := []struct {
+		c   Constraint
+		exp string
+	}{
+		{chk, "users_adult_age_mnrk_ckc"},
+		{uq, "users_account_id_email_mnrk_uqc"},
+		{ex, "bookings_room_id_during_mnrk_exc"},
+	}
+	for _, c := range cases {
+		act := c.c.ConstraintName()
+		if c.exp != act {
+			t.Errorf("want %q; got %q", c.exp, act)
+		}
+	}
+}
+
+// Unit test Constraint.Definition()
+func TestConstraintDefinition(t *testing.T) {
+	chk := Constraint{kind: CheckConstraint, expression: "price > 0"}
+	uq := Constraint{kind: UniqueConstraint, columns: []string{"a", "b"}}
+	ex := Constraint{kind: ExcludeConstraint, method: "spgist", elements: []ExcludeElement{{"during", "&&"}}}
+
+	cases := []struct {
+		c   Constraint
+		exp string
+	}{
+		{chk, "CHECK (price > 0)"},
+		{uq, "UNIQUE (a, b)"},
+		{ex, "EXCLUDE USING spgist (during WITH &&)"},
+	}
+	for _, c := range cases {
+		act := c.c.Definition()
+		if c.exp != act {
+			t.Errorf("want %q; got %q", c.exp, act)
+		}
+	}
+}
+
+// Unit test Constraint.Method()
+func TestConstraintMethod(t *testing.T) {
+	c := Constraint{}
+
+	exp := "gist"
+	act := c.Method() // Default
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	c.SetMethod("SPGIST")
+	exp = "spgist"
+	act = c.Method()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
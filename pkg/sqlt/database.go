@@ -1,13 +1,27 @@
 package sqlt
 
-import "github.com/iancoleman/strcase"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
 
 // Database ...
 type Database struct {
-	name           string
-	newName        string
-	owner          string
-	copyTargetName string
+	name               string
+	newName            string
+	owner              string
+	copyTargetName     string
+	encoding           string
+	lcCollate          string
+	lcCtype            string
+	template           string
+	tablespace         string
+	connectionLimit    int
+	hasConnectionLimit bool
+	localeProvider     string
+	icuLocale          string
 }
 
 // Name returns name.
@@ -49,3 +63,124 @@ func (t *Database) CopyTargetName() string {
 func (t *Database) SetCopyTargetName(name string) {
 	t.copyTargetName = strcase.ToSnake(name)
 }
+
+// Encoding returns the character set encoding, e.g. `UTF8`.
+func (t *Database) Encoding() string {
+	return t.encoding
+}
+
+// SetEncoding sets encoding.
+func (t *Database) SetEncoding(encoding string) {
+	t.encoding = encoding
+}
+
+// LCCollate returns the collation order (LC_COLLATE), e.g. `en_US.UTF-8`.
+func (t *Database) LCCollate() string {
+	return t.lcCollate
+}
+
+// SetLCCollate sets the collation order.
+func (t *Database) SetLCCollate(locale string) {
+	t.lcCollate = locale
+}
+
+// LCCtype returns the character classification (LC_CTYPE).
+func (t *Database) LCCtype() string {
+	return t.lcCtype
+}
+
+// SetLCCtype sets the character classification.
+func (t *Database) SetLCCtype(locale string) {
+	t.lcCtype = locale
+}
+
+// Template returns the name of the template database. Unless a template has
+// been set, Template returns `template0` if the encoding or a locale is set,
+// because they cannot differ from those of the default template, template1.
+func (t *Database) Template() string {
+	if t.template == "" && (t.encoding != "" || t.lcCollate != "" || t.lcCtype != "" || t.localeProvider != "" || t.icuLocale != "") {
+		return "template0"
+	}
+	return t.template
+}
+
+// SetTemplate sets template.
+func (t *Database) SetTemplate(name string) {
+	t.template = name
+}
+
+// Tablespace returns the default tablespace of the database.
+func (t *Database) Tablespace() string {
+	return t.tablespace
+}
+
+// SetTablespace sets tablespace.
+func (t *Database) SetTablespace(name string) {
+	t.tablespace = name
+}
+
+// ConnectionLimit returns the number of concurrent connections allowed, and
+// whether a limit has been set. A limit of -1 means no limit.
+func (t *Database) ConnectionLimit() (int, bool) {
+	return t.connectionLimit, t.hasConnectionLimit
+}
+
+// SetConnectionLimit sets connection limit.
+func (t *Database) SetConnectionLimit(limit int) {
+	t.connectionLimit = limit
+	t.hasConnectionLimit = true
+}
+
+// LocaleProvider returns the locale provider, `libc` or `icu`. It requires
+// PostgreSQL 15.
+func (t *Database) LocaleProvider() string {
+	return t.localeProvider
+}
+
+// SetLocaleProvider sets the locale provider after downcasing it.
+func (t *Database) SetLocaleProvider(provider string) {
+	t.localeProvider = strings.ToLower(provider)
+}
+
+// ICULocale returns the ICU locale, e.g. `und-u-ks-level2`, of a database
+// whose locale provider is icu.
+func (t *Database) ICULocale() string {
+	return t.icuLocale
+}
+
+// SetICULocale sets the ICU locale.
+func (t *Database) SetICULocale(locale string) {
+	t.icuLocale = locale
+}
+
+// Options returns the options of a CREATE DATABASE statement, other than the
+// owner, e.g. `TEMPLATE template0 ENCODING 'UTF8'`.
+func (t *Database) Options() string {
+	opts := make([]string, 0)
+	if v := t.Template(); v != "" {
+		opts = append(opts, "TEMPLATE "+v)
+	}
+	if t.encoding != "" {
+		opts = append(opts, "ENCODING "+quoteLiteral(t.encoding))
+	}
+	if t.localeProvider != "" {
+		opts = append(opts, "LOCALE_PROVIDER "+t.localeProvider)
+	}
+	if t.icuLocale != "" {
+		opts = append(opts, "ICU_LOCALE "+quoteLiteral(t.icuLocale))
+	}
+	if t.lcCollate != "" {
+		opts = append(opts, "LC_COLLATE "+quoteLiteral(t.lcCollate))
+	}
+	if t.lcCtype != "" {
+		opts = append(opts, "LC_CTYPE "+quoteLiteral(t.lcCtype))
+	}
+	if t.tablespace != "" {
+		opts = append(opts, "TABLESPACE "+t.tablespace)
+	}
+	if t.hasConnectionLimit {
+		opts = append(opts, fmt.Sprintf("CONNECTION LIMIT %d", t.connectionLimit))
+	}
+
+	return strings.Join(opts, " ")
+}
@@ -125,3 +125,33 @@ func TestDatabaseSetCopyTargetName(t *testing.T) {
 		t.Errorf("want %q; got %q", exp, act)
 	}
 }
+
+// Unit test CreateDBTmpl with options
+func TestCreateDBTmplOptions(t *testing.T) {
+	db := Database{name: "app", owner: "app_owner"}
+	db.SetEncoding("UTF8")
+	db.SetLocaleProvider("ICU")
+	db.SetICULocale("und-u-ks-level2")
+	db.SetLCCollate("en_US.UTF-8")
+	db.SetConnectionLimit(0)
+
+	exp := `CREATE DATABASE app OWNER app_owner TEMPLATE template0 ENCODING 'UTF8' LOCALE_PROVIDER icu ICU_LOCALE 'und-u-ks-level2' LC_COLLATE 'en_US.UTF-8' CONNECTION LIMIT 0;`
+	act, err := ProcessTmpl(&db, CreateDBTmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp != act {
+		t.Errorf("\nwant %q\n got %q", exp, act)
+	}
+
+	db = Database{name: "app", owner: "app_owner"}
+	db.SetTablespace("fast_ssd")
+	exp = `CREATE DATABASE app OWNER app_owner TABLESPACE fast_ssd;`
+	act, err = ProcessTmpl(&db, CreateDBTmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp != act {
+		t.Errorf("\nwant %q\n got %q", exp, act)
+	}
+}
@@ -0,0 +1,98 @@
+package sqlt
+
+import (
+	"strings"
+)
+
+// Enum ...
+type Enum struct {
+	name     string
+	values   []string
+	value    string
+	newValue string
+	before   string
+	after    string
+}
+
+// Name ...
+func (e *Enum) Name() string {
+	return e.name
+}
+
+// SetName ...
+func (e *Enum) SetName(name string) {
+	e.name = qualifiedName(name)
+}
+
+// Values returns the labels of the enum in sort order. Labels are case
+// sensitive and are not converted to snake_case.
+func (e *Enum) Values() []string {
+	return e.values
+}
+
+// AddValue ...
+func (e *Enum) AddValue(v string) {
+	e.values = append(e.values, v)
+}
+
+// ValueList returns the labels of the enum as a list of string literals.
+func (e *Enum) ValueList() string {
+	l := make([]string, 0, len(e.values))
+	for _, v := range e.values {
+		l = append(l, quoteLiteral(v))
+	}
+	return strings.Join(l, ", ")
+}
+
+// Value returns the label that is added or renamed, as a string literal.
+func (e *Enum) Value() string {
+	return quoteLiteral(e.value)
+}
+
+// SetValue ...
+func (e *Enum) SetValue(v string) {
+	e.value = v
+}
+
+// NewValue returns the new name of a renamed label, as a string literal.
+func (e *Enum) NewValue() string {
+	return quoteLiteral(e.newValue)
+}
+
+// SetNewValue ...
+func (e *Enum) SetNewValue(v string) {
+	e.newValue = v
+}
+
+// Before returns the label that an added label is sorted before, as a string
+// literal, or an empty string if there is none.
+func (e *Enum) Before() string {
+	if e.before == "" {
+		return ""
+	}
+	return quoteLiteral(e.before)
+}
+
+// SetBefore ...
+func (e *Enum) SetBefore(v string) {
+	e.before = v
+}
+
+// After returns the label that an added label is sorted after, as a string
+// literal, or an empty string if there is none.
+func (e *Enum) After() string {
+	if e.after == "" {
+		return ""
+	}
+	return quoteLiteral(e.after)
+}
+
+// SetAfter ...
+func (e *Enum) SetAfter(v string) {
+	e.after = v
+}
+
+// quoteLiteral returns s as a SQL string literal.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,60 @@
+package sqlt
+
+import (
+	"testing"
+)
+
+// Unit test Enum.SetName()
+func TestEnumSetName(t *testing.T) {
+	e := Enum{}
+	e.SetName("OrderStatus")
+
+	exp := "order_status"
+	act := e.Name()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test Enum.ValueList()
+func TestEnumValueList(t *testing.T) {
+	e := Enum{}
+	e.AddValue("Pending")
+	e.AddValue("won't ship")
+
+	exp := `'Pending', 'won''t ship'`
+	act := e.ValueList()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test Enum.Before() and Enum.After()
+func TestEnumBeforeAfter(t *testing.T) {
+	e := Enum{}
+
+	if act := e.Before(); act != "" {
+		t.Errorf("want %q; got %q", "", act)
+	}
+
+	e.SetAfter("shipped")
+	exp := "'shipped'"
+	act := e.After()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test AddEnumValueTmpl
+func TestAddEnumValueTmpl(t *testing.T) {
+	e := Enum{name: "order_status", value: "returned", before: "delivered"}
+
+	exp := `ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'returned' BEFORE 'delivered';`
+	act, err := ProcessTmpl(&e, AddEnumValueTmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
@@ -0,0 +1,60 @@
+package sqlt
+
+import (
+	"strings"
+)
+
+// Extension ...
+type Extension struct {
+	name    string
+	schema  string
+	version string
+}
+
+// Name returns the extension name, e.g. `uuid-ossp`. Extension names are not
+// converted to snake_case.
+func (e *Extension) Name() string {
+	return e.name
+}
+
+// SetName sets the extension name after downcasing it.
+func (e *Extension) SetName(name string) {
+	e.name = strings.ToLower(name)
+}
+
+// QuotedName returns Name quoted as an identifier if it is not a valid bare
+// identifier, e.g. `"uuid-ossp"`.
+func (e *Extension) QuotedName() string {
+	return quoteIdentifier(e.name)
+}
+
+// Schema returns the schema in which the objects of the extension are
+// created. An empty string means the current default schema.
+func (e *Extension) Schema() string {
+	return e.schema
+}
+
+// SetSchema ...
+func (e *Extension) SetSchema(name string) {
+	e.schema = qualifiedName(name)
+}
+
+// Version returns the version of the extension to install. An empty string
+// means the default version.
+func (e *Extension) Version() string {
+	return e.version
+}
+
+// SetVersion ...
+func (e *Extension) SetVersion(v string) {
+	e.version = v
+}
+
+// QuotedVersion returns Version as a string literal, or an empty string if
+// no version was set.
+func (e *Extension) QuotedVersion() string {
+	if e.version == "" {
+		return ""
+	}
+	return quoteLiteral(e.version)
+}
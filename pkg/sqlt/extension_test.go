@@ -0,0 +1,35 @@
+package sqlt
+
+import (
+	"testing"
+)
+
+// Unit test Extension.QuotedName()
+func TestExtensionQuotedName(t *testing.T) {
+	cases := [][]string{
+		{"pgcrypto", "pgcrypto"},
+		{"PG_TRGM", "pg_trgm"},
+		{"uuid-ossp", `"uuid-ossp"`},
+	}
+	for _, c := range cases {
+		e := Extension{}
+		e.SetName(c[0])
+		if exp, act := c[1], e.QuotedName(); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+	}
+}
+
+// Unit test CreateExtensionTmpl
+func TestCreateExtensionTmpl(t *testing.T) {
+	e := Extension{name: "citext"}
+
+	exp := "CREATE EXTENSION IF NOT EXISTS citext;"
+	act, err := ProcessTmpl(&e, CreateExtensionTmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
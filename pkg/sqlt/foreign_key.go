@@ -1,14 +1,25 @@
 package sqlt
 
 import (
+	"strings"
+
 	"github.com/iancoleman/strcase"
 )
 
 // ForeignKey ...
 type ForeignKey struct {
-	name                 string
-	referencedTableName  string
-	referencingTableName string
+	name                  string
+	referencedTableName   string
+	referencedColumnName  string
+	referencingTableName  string
+	referencingColumnName string
+	referencingColumnType string
+	onDelete              string
+	onUpdate              string
+	deferrable            bool
+	notNull               bool
+	existingColumn        bool
+	notValid              bool
 }
 
 // Name ...
@@ -21,9 +32,16 @@ func (f *ForeignKey) SetName(name string) {
 	f.name = strcase.ToSnake(name)
 }
 
-// ConstraintName ...
+// ConstraintName will generate a name from ReferencingTableName and
+// ReferencedTableName followed by `_mnrk_fkc`. If the referencing column was
+// set explicitly, the column name is used in place of ReferencedTableName so
+// that a table can reference the same parent more than once.
 func (f *ForeignKey) ConstraintName() string {
-	return f.ReferencingTableName() + "_" + f.ReferencedTableName() + "_mnrk_fkc"
+	table := unqualifiedName(f.ReferencingTableName())
+	if f.referencingColumnName != "" {
+		return table + "_" + f.ReferencingColumnName() + "_mnrk_fkc"
+	}
+	return table + "_" + unqualifiedName(f.ReferencedTableName()) + "_mnrk_fkc"
 }
 
 // ReferencedTableName ...
@@ -33,12 +51,20 @@ func (f *ForeignKey) ReferencedTableName() string {
 
 // SetReferencedTableName ...
 func (f *ForeignKey) SetReferencedTableName(name string) {
-	f.referencedTableName = strcase.ToSnake(name)
+	f.referencedTableName = qualifiedName(name)
 }
 
-// ReferencedColumnName ...
+// ReferencedColumnName returns the referenced column name. The default is `id`.
 func (f *ForeignKey) ReferencedColumnName() string {
-	return "id"
+	if f.referencedColumnName == "" {
+		return "id"
+	}
+	return f.referencedColumnName
+}
+
+// SetReferencedColumnName ...
+func (f *ForeignKey) SetReferencedColumnName(name string) {
+	f.referencedColumnName = strcase.ToSnake(name)
 }
 
 // ReferencingTableName ...
@@ -48,15 +74,97 @@ func (f *ForeignKey) ReferencingTableName() string {
 
 // SetReferencingTableName ...
 func (f *ForeignKey) SetReferencingTableName(name string) {
-	f.referencingTableName = strcase.ToSnake(name)
+	f.referencingTableName = qualifiedName(name)
 }
 
-// ReferencingColumnName ...
+// ReferencingColumnName returns the referencing column name. The default is
+// ReferencedTableName, without its schema, followed by `_id`.
 func (f *ForeignKey) ReferencingColumnName() string {
-	return f.ReferencedTableName() + "_id"
+	if f.referencingColumnName == "" {
+		return unqualifiedName(f.ReferencedTableName()) + "_id"
+	}
+	return f.referencingColumnName
+}
+
+// SetReferencingColumnName ...
+func (f *ForeignKey) SetReferencingColumnName(name string) {
+	f.referencingColumnName = strcase.ToSnake(name)
 }
 
-// ReferencingColumnType ...
+// ReferencingColumnType returns the referencing column type. The default is
+// `bigint`.
 func (f *ForeignKey) ReferencingColumnType() string {
-	return "bigint"
+	if f.referencingColumnType == "" {
+		return "bigint"
+	}
+	return f.referencingColumnType
+}
+
+// SetReferencingColumnType ...
+func (f *ForeignKey) SetReferencingColumnType(colType string) {
+	f.referencingColumnType = colType
+}
+
+// OnDelete returns the referential action taken when a referenced row is
+// deleted, e.g. `CASCADE`. An empty string means the server default.
+func (f *ForeignKey) OnDelete() string {
+	return f.onDelete
+}
+
+// SetOnDelete sets the ON DELETE referential action after upcasing it.
+func (f *ForeignKey) SetOnDelete(action string) {
+	f.onDelete = strings.ToUpper(action)
+}
+
+// OnUpdate returns the referential action taken when a referenced column is
+// updated, e.g. `CASCADE`. An empty string means the server default.
+func (f *ForeignKey) OnUpdate() string {
+	return f.onUpdate
+}
+
+// SetOnUpdate sets the ON UPDATE referential action after upcasing it.
+func (f *ForeignKey) SetOnUpdate(action string) {
+	f.onUpdate = strings.ToUpper(action)
+}
+
+// Deferrable reports whether the constraint is DEFERRABLE INITIALLY DEFERRED.
+func (f *ForeignKey) Deferrable() bool {
+	return f.deferrable
+}
+
+// SetDeferrable ...
+func (f *ForeignKey) SetDeferrable(deferrable bool) {
+	f.deferrable = deferrable
+}
+
+// NotNull reports whether the referencing column is added as NOT NULL.
+func (f *ForeignKey) NotNull() bool {
+	return f.notNull
+}
+
+// SetNotNull ...
+func (f *ForeignKey) SetNotNull(notNull bool) {
+	f.notNull = notNull
+}
+
+// ExistingColumn reports whether the constraint is attached to a column that
+// already exists instead of a new column.
+func (f *ForeignKey) ExistingColumn() bool {
+	return f.existingColumn
+}
+
+// SetExistingColumn ...
+func (f *ForeignKey) SetExistingColumn(existing bool) {
+	f.existingColumn = existing
+}
+
+// NotValid reports whether the constraint is added as NOT VALID, i.e. without
+// checking existing rows.
+func (f *ForeignKey) NotValid() bool {
+	return f.notValid
+}
+
+// SetNotValid ...
+func (f *ForeignKey) SetNotValid(notValid bool) {
+	f.notValid = notValid
 }
@@ -117,3 +117,55 @@ func TestForeignKeySetReferencingTableName(t *testing.T) {
 		}
 	}
 }
+
+// Unit test ForeignKey defaults for column name, referenced column and type.
+func TestForeignKeyDefaults(t *testing.T) {
+	fk := ForeignKey{}
+	fk.SetReferencingTableName("cars")
+	fk.SetReferencedTableName("people")
+
+	cases := [][]string{
+		{"people_id", fk.ReferencingColumnName()},
+		{"id", fk.ReferencedColumnName()},
+		{"bigint", fk.ReferencingColumnType()},
+		{"cars_people_mnrk_fkc", fk.ConstraintName()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+	}
+}
+
+// Unit test ForeignKey.ConstraintName() with an explicit referencing column.
+func TestForeignKeyConstraintNameWithColumn(t *testing.T) {
+	fk := ForeignKey{}
+	fk.SetReferencingTableName("transfers")
+	fk.SetReferencedTableName("accounts")
+	fk.SetReferencingColumnName("fromAccountID")
+
+	exp := "transfers_from_account_id_mnrk_fkc"
+	act := fk.ConstraintName()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test ForeignKey.SetOnDelete() and ForeignKey.SetOnUpdate()
+func TestForeignKeySetActions(t *testing.T) {
+	fk := ForeignKey{}
+	fk.SetOnDelete("set null")
+	fk.SetOnUpdate("cascade")
+
+	exp := "SET NULL"
+	act := fk.onDelete
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	exp = "CASCADE"
+	act = fk.onUpdate
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
@@ -0,0 +1,177 @@
+package sqlt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Function is a function or a procedure.
+type Function struct {
+	name      string
+	arguments []FunctionArgument
+	returns   string
+	language  string
+	body      string
+	procedure bool
+}
+
+// FunctionArgument is an argument of a function or a procedure.
+type FunctionArgument struct {
+	name       string
+	argType    string
+	defaultVal string
+}
+
+// Name ...
+func (a *FunctionArgument) Name() string {
+	return a.name
+}
+
+// SetName ...
+func (a *FunctionArgument) SetName(name string) {
+	a.name = strcase.ToSnake(name)
+}
+
+// Type ...
+func (a *FunctionArgument) Type() string {
+	return a.argType
+}
+
+// SetType ...
+func (a *FunctionArgument) SetType(t string) {
+	a.argType = t
+}
+
+// Default returns the default value expression of the argument.
+func (a *FunctionArgument) Default() string {
+	return a.defaultVal
+}
+
+// SetDefault ...
+func (a *FunctionArgument) SetDefault(expr string) {
+	a.defaultVal = expr
+}
+
+// Name ...
+func (f *Function) Name() string {
+	return f.name
+}
+
+// SetName ...
+func (f *Function) SetName(name string) {
+	f.name = qualifiedName(name)
+}
+
+// Arguments ...
+func (f *Function) Arguments() []FunctionArgument {
+	return f.arguments
+}
+
+// AddArgument ...
+func (f *Function) AddArgument(arg FunctionArgument) {
+	f.arguments = append(f.arguments, arg)
+}
+
+// ArgumentList returns the arguments, with their default values, formatted
+// for a CREATE FUNCTION statement.
+func (f *Function) ArgumentList() string {
+	l := make([]string, 0, len(f.arguments))
+	for _, a := range f.arguments {
+		s := strings.TrimSpace(a.Name() + " " + a.Type())
+		if a.Default() != "" {
+			s += " DEFAULT " + a.Default()
+		}
+		l = append(l, s)
+	}
+	return strings.Join(l, ", ")
+}
+
+// Signature returns the name and argument types that identify the function,
+// e.g. `add_tax(numeric, numeric)`.
+func (f *Function) Signature() string {
+	l := make([]string, 0, len(f.arguments))
+	for _, a := range f.arguments {
+		l = append(l, a.Type())
+	}
+	return fmt.Sprintf("%s(%s)", f.Name(), strings.Join(l, ", "))
+}
+
+// Returns returns the return type of a function. The default is void.
+func (f *Function) Returns() string {
+	if f.returns == "" {
+		return "void"
+	}
+	return f.returns
+}
+
+// SetReturns ...
+func (f *Function) SetReturns(t string) {
+	f.returns = t
+}
+
+// Language returns the implementation language. The default is plpgsql.
+func (f *Function) Language() string {
+	if f.language == "" {
+		return "plpgsql"
+	}
+	return f.language
+}
+
+// SetLanguage ...
+func (f *Function) SetLanguage(lang string) {
+	f.language = strings.ToLower(lang)
+}
+
+// Body returns the body of the function. If no body was set, Body returns a
+// scaffold for a plpgsql function.
+func (f *Function) Body() string {
+	if f.body != "" {
+		return f.body
+	}
+
+	switch {
+	case f.Language() != "plpgsql":
+		return "-- Write the body here."
+	case !f.procedure && strings.EqualFold(f.Returns(), "trigger"):
+		return "BEGIN\n\t-- Write the body here.\n\tRETURN NEW;\nEND;"
+	}
+	return "BEGIN\n\t-- Write the body here.\nEND;"
+}
+
+// SetBody sets the body of the function after trimming surrounding blank
+// lines.
+func (f *Function) SetBody(body string) {
+	f.body = strings.Trim(body, "\r\n")
+}
+
+// Procedure reports whether the function is a procedure, which has no return
+// type and is invoked with CALL.
+func (f *Function) Procedure() bool {
+	return f.procedure
+}
+
+// SetProcedure ...
+func (f *Function) SetProcedure(procedure bool) {
+	f.procedure = procedure
+}
+
+// Kind returns FUNCTION or PROCEDURE.
+func (f *Function) Kind() string {
+	if f.procedure {
+		return "PROCEDURE"
+	}
+	return "FUNCTION"
+}
+
+// DollarTag returns a dollar-quote tag, e.g. `$body$`, that does not occur
+// in the body, so that the body can be quoted without escaping.
+func (f *Function) DollarTag() string {
+	body := f.Body()
+	tag := "$body$"
+	for i := 1; strings.Contains(body, tag); i++ {
+		tag = fmt.Sprintf("$body%d$", i)
+	}
+	return tag
+}
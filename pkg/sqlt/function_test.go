@@ -0,0 +1,53 @@
+package sqlt
+
+import (
+	"testing"
+)
+
+// Unit test Function.Signature()
+func TestFunctionSignature(t *testing.T) {
+	f := Function{}
+	f.SetName("addTax")
+	f.AddArgument(FunctionArgument{name: "amount", argType: "numeric(10,2)"})
+	f.AddArgument(FunctionArgument{name: "rate", argType: "numeric", defaultVal: "0.2"})
+
+	exp := "add_tax(numeric(10,2), numeric)"
+	act := f.Signature()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	exp = "amount numeric(10,2), rate numeric DEFAULT 0.2"
+	act = f.ArgumentList()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test Function.DollarTag()
+func TestFunctionDollarTag(t *testing.T) {
+	cases := [][]string{
+		{"SELECT 1", "$body$"},
+		{"SELECT '$body$'", "$body1$"},
+		{"SELECT '$body$', '$body1$'", "$body2$"},
+	}
+	for _, c := range cases {
+		f := Function{}
+		f.SetBody(c[0])
+		if exp, act := c[1], f.DollarTag(); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+	}
+}
+
+// Unit test Function.Body() scaffolds.
+func TestFunctionBody(t *testing.T) {
+	f := Function{}
+	f.SetReturns("trigger")
+
+	exp := "BEGIN\n\t-- Write the body here.\n\tRETURN NEW;\nEND;"
+	act := f.Body()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
@@ -0,0 +1,163 @@
+package sqlt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Object types that privileges can be granted on.
+const (
+	TableObject    string = "table"
+	SequenceObject string = "sequence"
+	SchemaObject   string = "schema"
+)
+
+// privileges lists the privileges that can be granted on each object type.
+var privileges = map[string][]string{
+	TableObject:    {"SELECT", "INSERT", "UPDATE", "DELETE", "TRUNCATE", "REFERENCES", "TRIGGER"},
+	SequenceObject: {"USAGE", "SELECT", "UPDATE"},
+	SchemaObject:   {"USAGE", "CREATE"},
+}
+
+// Grant is a set of privileges on database objects that is granted to, or
+// revoked from, roles. A Grant on all the objects of a type in a schema may
+// be a grant of default privileges, which apply to objects created later.
+type Grant struct {
+	privileges      []string
+	objectType      string
+	objects         []string
+	allInSchema     bool
+	defaults        bool
+	forRole         string
+	roles           []string
+	withGrantOption bool
+}
+
+// Privileges returns the privileges, other than ALL, that can be granted on
+// an object type.
+func Privileges(objectType string) []string {
+	return privileges[objectType]
+}
+
+// ObjectType returns the type of the objects, e.g. `table`.
+func (g *Grant) ObjectType() string {
+	return g.objectType
+}
+
+// SetObjectType sets the type of the objects, after downcasing it.
+func (g *Grant) SetObjectType(objectType string) error {
+	objectType = strings.ToLower(objectType)
+	if _, ok := privileges[objectType]; !ok {
+		return fmt.Errorf("invalid object type %q: want table, sequence or schema", objectType)
+	}
+	g.objectType = objectType
+	return nil
+}
+
+// AddPrivilege adds a privilege, after upcasing it. ALL adds all the
+// privileges of the object type. SetObjectType must be called first.
+func (g *Grant) AddPrivilege(privilege string) error {
+	privilege = strings.ToUpper(privilege)
+	if privilege == "ALL" || privilege == "ALL PRIVILEGES" {
+		g.privileges = []string{"ALL PRIVILEGES"}
+		return nil
+	}
+	for _, p := range privileges[g.objectType] {
+		if p == privilege {
+			g.privileges = append(g.privileges, p)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s privilege %q: want %s or ALL", g.objectType, privilege, strings.Join(privileges[g.objectType], ", "))
+}
+
+// PrivilegeList returns the privileges formatted for a GRANT statement.
+func (g *Grant) PrivilegeList() string {
+	for _, p := range g.privileges {
+		if p == "ALL PRIVILEGES" {
+			return p
+		}
+	}
+	return strings.Join(g.privileges, ", ")
+}
+
+// AddObject adds the name of an object. If the Grant is on all the objects
+// in a schema, the name is a schema name.
+func (g *Grant) AddObject(name string) {
+	g.objects = append(g.objects, qualifiedName(name))
+}
+
+// AllInSchema reports whether the Grant is on all the tables or sequences in
+// the schemas added by AddObject.
+func (g *Grant) AllInSchema() bool {
+	return g.allInSchema
+}
+
+// SetAllInSchema ...
+func (g *Grant) SetAllInSchema(all bool) {
+	g.allInSchema = all
+}
+
+// Defaults reports whether the Grant is a grant of default privileges on the
+// tables or sequences created later in the schemas added by AddObject.
+func (g *Grant) Defaults() bool {
+	return g.defaults
+}
+
+// SetDefaults ...
+func (g *Grant) SetDefaults(defaults bool) {
+	g.defaults = defaults
+}
+
+// ForRole returns the role whose objects the default privileges apply to. An
+// empty string means the current user.
+func (g *Grant) ForRole() string {
+	return g.forRole
+}
+
+// SetForRole ...
+func (g *Grant) SetForRole(name string) {
+	g.forRole = roleName(name)
+}
+
+// Target returns the objects formatted for a GRANT statement, e.g.
+// `TABLE users, orders` or `ALL TABLES IN SCHEMA billing`. For default
+// privileges, the schema is part of the enclosing statement, so Target is
+// `TABLES`.
+func (g *Grant) Target() string {
+	plural := strings.ToUpper(g.objectType) + "S"
+	switch {
+	case g.defaults:
+		return plural
+	case g.allInSchema:
+		return fmt.Sprintf("ALL %s IN SCHEMA %s", plural, strings.Join(g.objects, ", "))
+	}
+	return fmt.Sprintf("%s %s", strings.ToUpper(g.objectType), strings.Join(g.objects, ", "))
+}
+
+// Schemas returns the schemas of a grant of default privileges, joined by
+// commas.
+func (g *Grant) Schemas() string {
+	return strings.Join(g.objects, ", ")
+}
+
+// AddRole adds a role that the privileges are granted to or revoked from.
+func (g *Grant) AddRole(name string) {
+	g.roles = append(g.roles, roleName(name))
+}
+
+// RoleList returns the roles joined by commas.
+func (g *Grant) RoleList() string {
+	return strings.Join(g.roles, ", ")
+}
+
+// WithGrantOption reports whether the roles may grant the privileges to
+// other roles.
+func (g *Grant) WithGrantOption() bool {
+	return g.withGrantOption
+}
+
+// SetWithGrantOption ...
+func (g *Grant) SetWithGrantOption(option bool) {
+	g.withGrantOption = option
+}
@@ -0,0 +1,44 @@
+package sqlt
+
+import (
+	"testing"
+)
+
+// Unit test GrantTmpl
+func TestGrantTmpl(t *testing.T) {
+	g := Grant{}
+	g.SetObjectType("SCHEMA")
+	g.AddPrivilege("usage")
+	g.AddPrivilege("create")
+	g.AddObject("Billing")
+	g.AddRole("appUser")
+	g.SetWithGrantOption(true)
+
+	exp := "GRANT USAGE, CREATE ON SCHEMA billing TO app_user WITH GRANT OPTION;"
+	act, err := ProcessTmpl(&g, GrantTmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test Grant.AddPrivilege()
+func TestGrantAddPrivilege(t *testing.T) {
+	g := Grant{}
+	err := g.SetObjectType("view")
+	if err == nil {
+		t.Error("want error for object type view; got nil")
+	}
+
+	g.SetObjectType("sequence")
+	if err := g.AddPrivilege("delete"); err == nil {
+		t.Error("want error for sequence privilege DELETE; got nil")
+	}
+	g.AddPrivilege("usage")
+	g.AddPrivilege("all")
+	if exp, act := "ALL PRIVILEGES", g.PrivilegeList(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
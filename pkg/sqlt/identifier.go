@@ -0,0 +1,84 @@
+package sqlt
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/iancoleman/strcase"
+)
+
+// maxIdentifierLength is the maximum length in bytes of a PostgreSQL
+// identifier. Longer identifiers are silently truncated by the server.
+const maxIdentifierLength = 63
+
+// identifier joins base and suffix into a name. If the name would be longer
+// than maxIdentifierLength, base is truncated and a hash of the complete base
+// is appended to it, so that the name is deterministic and names that differ
+// only after the truncation point remain distinct.
+func identifier(base, suffix string) string {
+	name := base + suffix
+	if len(name) <= maxIdentifierLength {
+		return name
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(base))
+	hash := fmt.Sprintf("_%08x", h.Sum32())
+
+	// Truncate base on a rune boundary.
+	n := maxIdentifierLength - len(hash) - len(suffix)
+	for n > 0 && !utf8.RuneStart(base[n]) {
+		n--
+	}
+
+	return base[:n] + hash + suffix
+}
+
+// qualifiedName converts each part of a name that may be qualified by a
+// schema name, e.g. `Billing.InvoiceItems`, to snake_case.
+func qualifiedName(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = strcase.ToSnake(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// unqualifiedName returns a name without its schema qualification, if any.
+func unqualifiedName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// schemaOf returns the schema qualification of a name followed by a dot, or
+// an empty string if the name is not qualified.
+func schemaOf(name string) string {
+	return name[:strings.LastIndex(name, ".")+1]
+}
+
+// SplitQualifiedName splits a name that may be qualified by a schema name into
+// the schema name, or an empty string if the name is not qualified, and the
+// unqualified name.
+func SplitQualifiedName(name string) (string, string) {
+	return strings.TrimSuffix(schemaOf(name), "."), unqualifiedName(name)
+}
+
+// quoteIdentifier returns name unchanged if it is a valid bare identifier, or
+// as a quoted identifier otherwise.
+func quoteIdentifier(name string) string {
+	bare := name != ""
+	for i, r := range name {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z':
+		case i > 0 && (r == '$' || r >= '0' && r <= '9'):
+		default:
+			bare = false
+		}
+	}
+	if bare {
+		return name
+	}
+
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
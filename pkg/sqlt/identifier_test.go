@@ -0,0 +1,17 @@
+package sqlt
+
+import "testing"
+
+// Unit test SplitQualifiedName()
+func TestSplitQualifiedName(t *testing.T) {
+	cases := [][]string{
+		{"billing.active_invoices", "billing", "active_invoices"},
+		{"active_invoices", "", "active_invoices"},
+	}
+	for _, c := range cases {
+		schema, name := SplitQualifiedName(c[0])
+		if schema != c[1] || name != c[2] {
+			t.Errorf("%q: want %q, %q; got %q, %q", c[0], c[1], c[2], schema, name)
+		}
+	}
+}
@@ -2,20 +2,59 @@ package sqlt
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/iancoleman/strcase"
 )
 
 // Index ...
 type Index struct {
-	tableName  string
-	columnName string
+	name         string
+	tableName    string
+	columnName   string
+	columns      []IndexColumn
+	unique       bool
+	method       string
+	where        string
+	include      []string
+	concurrently bool
 }
 
-// Name will generate a name from TableName and ColumnName followed by `_mnrk_idx`.
+// IndexColumn is a column or expression, with options, that is part of an
+// index key.
+type IndexColumn struct {
+	name       string
+	expression string
+	opClass    string
+	order      string
+	nulls      string
+}
+
+// Name returns the index name, without a schema qualification. Unless a name
+// has been set, Name will generate a name from TableName and the key columns
+// followed by `_mnrk_idx`, which is truncated to fit PostgreSQL's 63-byte
+// identifier limit.
 func (idx *Index) Name() string {
-	name := fmt.Sprintf("%s_%s_mnrk_idx", idx.TableName(), idx.ColumnName())
-	return name
+	if idx.name != "" {
+		return unqualifiedName(idx.name)
+	}
+
+	base := fmt.Sprintf("%s_%s", unqualifiedName(idx.TableName()), idx.ColumnLabel())
+	return identifier(base, "_mnrk_idx")
+}
+
+// QualifiedName returns Name qualified by the schema of the index, which is
+// the schema of its table unless the name set by SetName is qualified.
+func (idx *Index) QualifiedName() string {
+	if s := schemaOf(idx.name); s != "" {
+		return s + idx.Name()
+	}
+	return schemaOf(idx.TableName()) + idx.Name()
+}
+
+// SetName ...
+func (idx *Index) SetName(name string) {
+	idx.name = qualifiedName(name)
 }
 
 // TableName ...
@@ -25,7 +64,7 @@ func (idx *Index) TableName() string {
 
 // SetTableName ...
 func (idx *Index) SetTableName(name string) {
-	idx.tableName = strcase.ToSnake(name)
+	idx.tableName = qualifiedName(name)
 }
 
 // ColumnName ...
@@ -37,3 +76,183 @@ func (idx *Index) ColumnName() string {
 func (idx *Index) SetColumnName(name string) {
 	idx.columnName = strcase.ToSnake(name)
 }
+
+// Columns returns the key columns. If no columns have been added, the column
+// set by SetColumnName is returned.
+func (idx *Index) Columns() []IndexColumn {
+	if len(idx.columns) == 0 && idx.columnName != "" {
+		return []IndexColumn{{name: idx.columnName}}
+	}
+	return idx.columns
+}
+
+// AddColumn adds a key column.
+func (idx *Index) AddColumn(col IndexColumn) {
+	idx.columns = append(idx.columns, col)
+}
+
+// ColumnList returns the key columns formatted for a CREATE INDEX statement.
+func (idx *Index) ColumnList() string {
+	defs := make([]string, 0)
+	for _, c := range idx.Columns() {
+		defs = append(defs, c.Definition())
+	}
+	return strings.Join(defs, ", ")
+}
+
+// ColumnLabel returns the labels of the key columns joined by underscores.
+func (idx *Index) ColumnLabel() string {
+	labels := make([]string, 0)
+	for _, c := range idx.Columns() {
+		labels = append(labels, c.Label())
+	}
+	return strings.Join(labels, "_")
+}
+
+// Unique ...
+func (idx *Index) Unique() bool {
+	return idx.unique
+}
+
+// SetUnique ...
+func (idx *Index) SetUnique(unique bool) {
+	idx.unique = unique
+}
+
+// Method returns the index access method, e.g. `gin`. An empty string means
+// the server default (btree).
+func (idx *Index) Method() string {
+	return idx.method
+}
+
+// SetMethod ...
+func (idx *Index) SetMethod(method string) {
+	idx.method = strings.ToLower(method)
+}
+
+// Where returns the predicate of a partial index.
+func (idx *Index) Where() string {
+	return idx.where
+}
+
+// SetWhere ...
+func (idx *Index) SetWhere(predicate string) {
+	idx.where = predicate
+}
+
+// Include returns the non-key columns of a covering index.
+func (idx *Index) Include() []string {
+	return idx.include
+}
+
+// AddInclude adds a non-key column.
+func (idx *Index) AddInclude(name string) {
+	idx.include = append(idx.include, strcase.ToSnake(name))
+}
+
+// IncludeList returns the non-key columns formatted for an INCLUDE clause.
+func (idx *Index) IncludeList() string {
+	return strings.Join(idx.include, ", ")
+}
+
+// Concurrently reports whether the index is built or dropped CONCURRENTLY,
+// without blocking writes to the table.
+func (idx *Index) Concurrently() bool {
+	return idx.concurrently
+}
+
+// SetConcurrently ...
+func (idx *Index) SetConcurrently(concurrently bool) {
+	idx.concurrently = concurrently
+}
+
+// Name ...
+func (c *IndexColumn) Name() string {
+	return c.name
+}
+
+// SetName ...
+func (c *IndexColumn) SetName(name string) {
+	c.name = strcase.ToSnake(name)
+}
+
+// Expression returns the expression of an expression index column.
+func (c *IndexColumn) Expression() string {
+	return c.expression
+}
+
+// SetExpression sets the expression of an expression index column. The
+// expression is wrapped in parentheses if necessary.
+func (c *IndexColumn) SetExpression(expr string) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "(") || !strings.HasSuffix(expr, ")") {
+		expr = "(" + expr + ")"
+	}
+	c.expression = expr
+}
+
+// OpClass returns the operator class, e.g. `gin_trgm_ops`.
+func (c *IndexColumn) OpClass() string {
+	return c.opClass
+}
+
+// SetOpClass ...
+func (c *IndexColumn) SetOpClass(opClass string) {
+	c.opClass = opClass
+}
+
+// Order returns the sort order, `ASC` or `DESC`.
+func (c *IndexColumn) Order() string {
+	return c.order
+}
+
+// SetOrder sets the sort order after upcasing it.
+func (c *IndexColumn) SetOrder(order string) {
+	c.order = strings.ToUpper(order)
+}
+
+// Nulls returns the position of nulls, `FIRST` or `LAST`.
+func (c *IndexColumn) Nulls() string {
+	return c.nulls
+}
+
+// SetNulls sets the position of nulls after upcasing it.
+func (c *IndexColumn) SetNulls(nulls string) {
+	c.nulls = strings.ToUpper(nulls)
+}
+
+// Label returns the column name, or for an expression a snake_case label
+// derived from the expression, for use in an index name.
+func (c *IndexColumn) Label() string {
+	if c.expression == "" {
+		return c.name
+	}
+
+	label := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return ' '
+	}, strings.ToLower(c.expression))
+
+	return strings.Join(strings.Fields(label), "_")
+}
+
+// Definition returns the column formatted for a CREATE INDEX statement.
+func (c *IndexColumn) Definition() string {
+	parts := []string{c.name}
+	if c.expression != "" {
+		parts[0] = c.expression
+	}
+	if c.opClass != "" {
+		parts = append(parts, c.opClass)
+	}
+	if c.order != "" {
+		parts = append(parts, c.order)
+	}
+	if c.nulls != "" {
+		parts = append(parts, "NULLS "+c.nulls)
+	}
+
+	return strings.Join(parts, " ")
+}
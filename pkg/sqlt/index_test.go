@@ -1,6 +1,7 @@
 package sqlt
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -102,3 +103,111 @@ func TestIndexSetColumnName(t *testing.T) {
 		}
 	}
 }
+
+// Unit test Index.Name() with names longer than PostgreSQL's identifier limit.
+func TestIndexNameTruncated(t *testing.T) {
+	idx := Index{}
+	idx.SetTableName("customer_subscription_billing_events")
+	idx.AddColumn(IndexColumn{name: "billing_period_start"})
+	idx.AddColumn(IndexColumn{name: "billing_period_end"})
+
+	act := idx.Name()
+	if l := len(act); l > 63 {
+		t.Errorf("want at most 63 bytes; got %d (%q)", l, act)
+	}
+	if !strings.HasSuffix(act, "_mnrk_idx") {
+		t.Errorf("want suffix %q; got %q", "_mnrk_idx", act)
+	}
+
+	// Verify that the name is deterministic and depends on every column.
+	if again := idx.Name(); again != act {
+		t.Errorf("want %q; got %q", act, again)
+	}
+	other := Index{}
+	other.SetTableName("customer_subscription_billing_events")
+	other.AddColumn(IndexColumn{name: "billing_period_start"})
+	other.AddColumn(IndexColumn{name: "billing_period_ended"})
+	if o := other.Name(); o == act {
+		t.Errorf("want distinct names; got %q twice", act)
+	}
+}
+
+// Unit test Index.ColumnList()
+func TestIndexColumnList(t *testing.T) {
+	idx := Index{}
+	idx.AddColumn(IndexColumn{name: "last_name", order: "DESC", nulls: "LAST"})
+	idx.AddColumn(IndexColumn{name: "title", opClass: "text_pattern_ops"})
+	ec := IndexColumn{}
+	ec.SetExpression("lower(email)")
+	idx.AddColumn(ec)
+
+	exp := "last_name DESC NULLS LAST, title text_pattern_ops, (lower(email))"
+	act := idx.ColumnList()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	exp = "last_name_title_lower_email"
+	act = idx.ColumnLabel()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test Index.Columns() falling back to the column set by SetColumnName().
+func TestIndexColumns(t *testing.T) {
+	idx := Index{}
+	idx.SetColumnName("color")
+
+	cols := idx.Columns()
+	if len(cols) != 1 {
+		t.Fatalf("want 1 column; got %d", len(cols))
+	}
+
+	exp := "color"
+	act := cols[0].Name()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test identifier()
+func TestIdentifier(t *testing.T) {
+	exp := "users_email_mnrk_idx"
+	act := identifier("users_email", "_mnrk_idx")
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	base := strings.Repeat("a", 80)
+	act = identifier(base, "_mnrk_idx")
+	if l := len(act); l != 63 {
+		t.Errorf("want 63 bytes; got %d (%q)", l, act)
+	}
+}
+
+// Unit test Index.Name() and Index.QualifiedName() with a schema-qualified table.
+func TestIndexQualifiedName(t *testing.T) {
+	idx := Index{}
+	idx.SetTableName("billing.invoices")
+	idx.SetColumnName("total")
+
+	exp := "invoices_total_mnrk_idx"
+	act := idx.Name()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	exp = "billing.invoices_total_mnrk_idx"
+	act = idx.QualifiedName()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	idx.SetName("archive.InvoicesByTotal")
+	exp = "archive.invoices_by_total"
+	act = idx.QualifiedName()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
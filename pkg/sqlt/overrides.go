@@ -0,0 +1,191 @@
+package sqlt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+)
+
+// TemplateFileExt is the extension of a template file.
+const TemplateFileExt string = ".sql.tmpl"
+
+// overridable is a template that can be overridden by a template file, with
+// the data that the template is applied to.
+type overridable struct {
+	tmpl *string
+	data func() interface{}
+}
+
+// overridables maps the name of each template file to the template it
+// overrides.
+var overridables = map[string]overridable{
+	"create_db":                      {&CreateDBTmpl, func() interface{} { return new(Database) }},
+	"copy_db":                        {&CopyDBTmpl, func() interface{} { return new(Database) }},
+	"drop_db":                        {&DropDBTmpl, func() interface{} { return new(Database) }},
+	"rename_db":                      {&RenameDBTmpl, func() interface{} { return new(Database) }},
+	"create_schema":                  {&CreateSchemaTmpl, func() interface{} { return new(Schema) }},
+	"drop_schema":                    {&DropSchemaTmpl, func() interface{} { return new(Schema) }},
+	"create_partitions":              {&CreatePartitionsTmpl, func() interface{} { return []*Partition{new(Partition)} }},
+	"drop_partitions":                {&DropPartitionsTmpl, func() interface{} { return []*Partition{new(Partition)} }},
+	"attach_partition":               {&AttachPartitionTmpl, func() interface{} { return new(Partition) }},
+	"detach_partition":               {&DetachPartitionTmpl, func() interface{} { return new(Partition) }},
+	"create_role":                    {&CreateRoleTmpl, func() interface{} { return new(Role) }},
+	"drop_role":                      {&DropRoleTmpl, func() interface{} { return new(Role) }},
+	"grant":                          {&GrantTmpl, func() interface{} { return new(Grant) }},
+	"revoke":                         {&RevokeTmpl, func() interface{} { return new(Grant) }},
+	"enable_row_level_security":      {&EnableRowLevelSecurityTmpl, func() interface{} { return new(Policy) }},
+	"disable_row_level_security":     {&DisableRowLevelSecurityTmpl, func() interface{} { return new(Policy) }},
+	"create_policy":                  {&CreatePolicyTmpl, func() interface{} { return new(Policy) }},
+	"drop_policy":                    {&DropPolicyTmpl, func() interface{} { return new(Policy) }},
+	"comment":                        {&CommentTmpl, func() interface{} { return new(Comment) }},
+	"create_extension":               {&CreateExtensionTmpl, func() interface{} { return new(Extension) }},
+	"drop_extension":                 {&DropExtensionTmpl, func() interface{} { return new(Extension) }},
+	"create_table":                   {&CreateTableTmpl, func() interface{} { return new(Table) }},
+	"create_set_updated_at_function": {&CreateSetUpdatedAtFunctionTmpl, func() interface{} { return nil }},
+	"drop_set_updated_at_function":   {&DropSetUpdatedAtFunctionTmpl, func() interface{} { return nil }},
+	"drop_table":                     {&DropTableTmpl, func() interface{} { return new(Table) }},
+	"rename_table":                   {&RenameTableTmpl, func() interface{} { return new(Table) }},
+	"add_column":                     {&AddColumnTmpl, func() interface{} { return new(Table) }},
+	"drop_column":                    {&DropColumnTmpl, func() interface{} { return new(Table) }},
+	"recast_column":                  {&RecastColumnTmpl, func() interface{} { return new(Table) }},
+	"rename_column":                  {&RenameColumnTmpl, func() interface{} { return new(Table) }},
+	"create_index":                   {&CreateIndexTmpl, func() interface{} { return new(Index) }},
+	"drop_index":                     {&DropIndexTmpl, func() interface{} { return new(Index) }},
+	"add_foreign_key":                {&AddForeignKeyTmpl, func() interface{} { return new(ForeignKey) }},
+	"validate_foreign_key":           {&ValidateForeignKeyTmpl, func() interface{} { return new(ForeignKey) }},
+	"drop_foreign_key":               {&DropForeignKeyTmpl, func() interface{} { return new(ForeignKey) }},
+	"add_constraint":                 {&AddConstraintTmpl, func() interface{} { return new(Constraint) }},
+	"validate_constraint":            {&ValidateConstraintTmpl, func() interface{} { return new(Constraint) }},
+	"drop_constraint":                {&DropConstraintTmpl, func() interface{} { return new(Constraint) }},
+	"create_view":                    {&CreateViewTmpl, func() interface{} { return new(View) }},
+	"drop_view":                      {&DropViewTmpl, func() interface{} { return new(View) }},
+	"refresh_materialized_view":      {&RefreshMaterializedViewTmpl, func() interface{} { return new(View) }},
+	"create_function":                {&CreateFunctionTmpl, func() interface{} { return new(Function) }},
+	"drop_function":                  {&DropFunctionTmpl, func() interface{} { return new(Function) }},
+	"create_trigger":                 {&CreateTriggerTmpl, func() interface{} { return new(Trigger) }},
+	"drop_trigger":                   {&DropTriggerTmpl, func() interface{} { return new(Trigger) }},
+	"create_enum":                    {&CreateEnumTmpl, func() interface{} { return new(Enum) }},
+	"drop_enum":                      {&DropEnumTmpl, func() interface{} { return new(Enum) }},
+	"add_enum_value":                 {&AddEnumValueTmpl, func() interface{} { return new(Enum) }},
+	"rename_enum_value":              {&RenameEnumValueTmpl, func() interface{} { return new(Enum) }},
+}
+
+// defaultTemplates holds the compiled-in text of each overridable template,
+// keyed by the name of its template file.
+var defaultTemplates = func() map[string]string {
+	d := make(map[string]string, len(overridables))
+	for name, o := range overridables {
+		d[name] = *o.tmpl
+	}
+	return d
+}()
+
+// templateFiles maps the name of each overridden template to its template
+// file, so that errors in the template name the file.
+var templateFiles = make(map[string]string)
+
+// templateNameMarker starts the text of an overridden template with a template
+// comment that holds the name of the template, e.g.
+// "{{/* monarch:template drop_table */}}". ProcessTmpl uses the name to find
+// the template file. The comment renders as nothing and, being on the first
+// line, leaves the line numbers of errors unchanged.
+const templateNameMarker string = "{{/* monarch:template "
+
+// templateName returns the name of an overridden template from its text, or
+// an empty string if the text is not that of an overridden template.
+func templateName(text string) string {
+	if !strings.HasPrefix(text, templateNameMarker) {
+		return ""
+	}
+	text = strings.TrimPrefix(text, templateNameMarker)
+	i := strings.Index(text, " */}}")
+	if i < 0 {
+		return ""
+	}
+
+	return text[:i]
+}
+
+// TemplateFileNames returns the sorted names of the template files that can
+// override templates, e.g. "create_table.sql.tmpl".
+func TemplateFileNames() []string {
+	names := make([]string, 0, len(overridables))
+	for name := range overridables {
+		names = append(names, name+TemplateFileExt)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// DefaultTemplate returns the compiled-in text of the template overridden by
+// the named template file.
+func DefaultTemplate(fileName string) (string, bool) {
+	t, ok := defaultTemplates[strings.TrimSuffix(fileName, TemplateFileExt)]
+	return t, ok
+}
+
+// LoadTemplates overrides templates with the template files in the directory
+// specified by "dirname". Each template is parsed and applied to empty data,
+// and an error names the template file and the line of the error. Files
+// without the template file extension are ignored. If the directory does not
+// exist, LoadTemplates does nothing.
+func LoadTemplates(dirname string) error {
+	files, err := ioutil.ReadDir(dirname)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), TemplateFileExt) {
+			continue
+		}
+		path := dirname + "/" + f.Name()
+		name := strings.TrimSuffix(f.Name(), TemplateFileExt)
+		o, ok := overridables[name]
+		if !ok {
+			return fmt.Errorf("%s: unknown template file", path)
+		}
+
+		text, err := fileutil.ReadFileAsString(path)
+		if err != nil {
+			return err
+		}
+		err = validateTemplate(path, text, o.data())
+		if err != nil {
+			return err
+		}
+
+		*o.tmpl = templateNameMarker + name + " */}}" + text
+		templateFiles[name] = path
+	}
+
+	return nil
+}
+
+// validateTemplate parses a template and applies it to data. Errors are
+// prefixed by "template: <name>:<line>".
+func validateTemplate(name, text string, data interface{}) error {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(ioutil.Discard, data)
+}
+
+// resetTemplates restores the compiled-in templates.
+func resetTemplates() {
+	for name, o := range overridables {
+		*o.tmpl = defaultTemplates[name]
+	}
+	templateFiles = make(map[string]string)
+}
@@ -0,0 +1,101 @@
+package sqlt
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kevinsapp/monarch/pkg/fileutil"
+)
+
+const tmpTestTemplatesDir string = "tmp/test/templates"
+
+// Unit test validateTemplate() with the compiled-in templates
+func TestValidateDefaultTemplates(t *testing.T) {
+	for _, fn := range TemplateFileNames() {
+		text, ok := DefaultTemplate(fn)
+		if !ok {
+			t.Fatalf("no default template for %s", fn)
+		}
+		o := overridables[strings.TrimSuffix(fn, TemplateFileExt)]
+		err := validateTemplate(fn, text, o.data())
+		if err != nil {
+			t.Errorf("%s: %s", fn, err)
+		}
+	}
+}
+
+// Unit test LoadTemplates()
+func TestLoadTemplates(t *testing.T) {
+	fileutil.MkdirP(tmpTestTemplatesDir)
+	defer os.RemoveAll("tmp") // Do cleanup
+	defer resetTemplates()
+
+	// A missing directory overrides nothing.
+	err := LoadTemplates(tmpTestTemplatesDir + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Override drop_table.sql.tmpl.
+	fn := tmpTestTemplatesDir + "/drop_table.sql.tmpl"
+	err = fileutil.CreateAndWriteString(fn, "DROP TABLE IF EXISTS {{.Name}};")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = LoadTemplates(tmpTestTemplatesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbl := Table{}
+	tbl.SetName("users")
+	act, err := ProcessTmpl(&tbl, DropTableTmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "DROP TABLE IF EXISTS users;"; exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	// Errors in an override name its template file, even if another override
+	// has identical text.
+	resetTemplates()
+	text := "DROP TABLE {{.Name}};{{if .Name}}\n{{index .Columns 5}}{{end}}"
+	for _, n := range []string{"drop_table", "rename_table"} {
+		err = fileutil.CreateAndWriteString(tmpTestTemplatesDir+"/"+n+TemplateFileExt, text)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = LoadTemplates(tmpTestTemplatesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, tmpl := range map[string]string{"drop_table": DropTableTmpl, "rename_table": RenameTableTmpl} {
+		_, err = ProcessTmpl(&tbl, tmpl)
+		exp := tmpTestTemplatesDir + "/" + n + TemplateFileExt + ":2"
+		if err == nil || !strings.Contains(err.Error(), exp) {
+			t.Errorf("want error naming %q; got %v", exp, err)
+		}
+	}
+
+	// Errors name the template file and line.
+	cases := [][]string{
+		{"drop_table.sql.tmpl", "DROP TABLE\n{{.Name}", fn + ":2"},
+		{"drop_table.sql.tmpl", "DROP TABLE\n\n{{.DeletedAt}};", fn + ":3"},
+		{"drop_tables.sql.tmpl", "DROP TABLE {{.Name}};", tmpTestTemplatesDir + "/drop_tables.sql.tmpl"},
+	}
+	for _, c := range cases {
+		resetTemplates()
+		os.RemoveAll(tmpTestTemplatesDir)
+		fileutil.MkdirP(tmpTestTemplatesDir)
+		err = fileutil.CreateAndWriteString(tmpTestTemplatesDir+"/"+c[0], c[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = LoadTemplates(tmpTestTemplatesDir)
+		if err == nil || !strings.Contains(err.Error(), c[2]) {
+			t.Errorf("want error naming %q; got %v", c[2], err)
+		}
+	}
+}
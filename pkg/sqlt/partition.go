@@ -0,0 +1,154 @@
+package sqlt
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Partitioning strategies supported by Table and Partition.
+const (
+	RangePartition string = "range"
+	ListPartition  string = "list"
+	HashPartition  string = "hash"
+)
+
+// Partition is a partition of a partitioned table.
+type Partition struct {
+	name         string
+	parent       string
+	from         string
+	to           string
+	values       []string
+	modulus      int
+	remainder    int
+	isDefault    bool
+	concurrently bool
+}
+
+// Name returns the partition name. Unless a name has been set, Name will
+// generate a name from Parent and the partition bound, e.g.
+// `events_2024_01_01` for a range partition from '2024-01-01'.
+func (p *Partition) Name() string {
+	if p.name != "" {
+		return p.name
+	}
+
+	var label string
+	switch {
+	case p.isDefault:
+		label = "default"
+	case p.modulus > 0:
+		label = fmt.Sprintf("%d_of_%d", p.remainder, p.modulus)
+	case len(p.values) > 0:
+		label = strings.Join(p.values, "_")
+	default:
+		label = p.from
+	}
+
+	return schemaOf(p.parent) + identifier(unqualifiedName(p.parent), "_"+labelOf(label))
+}
+
+// SetName ...
+func (p *Partition) SetName(name string) {
+	p.name = qualifiedName(name)
+}
+
+// Parent returns the name of the partitioned table.
+func (p *Partition) Parent() string {
+	return p.parent
+}
+
+// SetParent ...
+func (p *Partition) SetParent(name string) {
+	p.parent = qualifiedName(name)
+}
+
+// SetRange sets the bounds of a range partition. The lower bound is
+// inclusive and the upper bound is exclusive.
+func (p *Partition) SetRange(from, to string) {
+	p.from = from
+	p.to = to
+}
+
+// AddValue adds a value to the bound of a list partition.
+func (p *Partition) AddValue(v string) {
+	p.values = append(p.values, v)
+}
+
+// SetHash sets the bound of a hash partition.
+func (p *Partition) SetHash(modulus, remainder int) {
+	p.modulus = modulus
+	p.remainder = remainder
+}
+
+// Default reports whether the partition is the default partition, which
+// holds the rows that do not fit in any other partition.
+func (p *Partition) Default() bool {
+	return p.isDefault
+}
+
+// SetDefault ...
+func (p *Partition) SetDefault(isDefault bool) {
+	p.isDefault = isDefault
+}
+
+// Concurrently reports whether the partition is detached without blocking
+// queries on the parent table. It requires PostgreSQL 14, and the statement
+// cannot be executed inside a transaction block.
+func (p *Partition) Concurrently() bool {
+	return p.concurrently
+}
+
+// SetConcurrently ...
+func (p *Partition) SetConcurrently(concurrently bool) {
+	p.concurrently = concurrently
+}
+
+// Bound returns the partition bound, e.g. `FOR VALUES IN ('a', 'b')`.
+func (p *Partition) Bound() string {
+	switch {
+	case p.isDefault:
+		return "DEFAULT"
+	case p.modulus > 0:
+		return fmt.Sprintf("FOR VALUES WITH (MODULUS %d, REMAINDER %d)", p.modulus, p.remainder)
+	case len(p.values) > 0:
+		l := make([]string, 0, len(p.values))
+		for _, v := range p.values {
+			l = append(l, boundValue(v))
+		}
+		return fmt.Sprintf("FOR VALUES IN (%s)", strings.Join(l, ", "))
+	}
+
+	return fmt.Sprintf("FOR VALUES FROM (%s) TO (%s)", boundValue(p.from), boundValue(p.to))
+}
+
+// boundValue returns a partition bound value as a string literal, except for
+// the MINVALUE, MAXVALUE and NULL keywords.
+func boundValue(v string) string {
+	switch strings.ToUpper(v) {
+	case "MINVALUE", "MAXVALUE", "NULL":
+		return strings.ToUpper(v)
+	}
+	return quoteLiteral(v)
+}
+
+// labelOf converts a bound value to a label for a partition name by
+// downcasing it and replacing each run of other characters than letters and
+// digits with an underscore.
+func labelOf(v string) string {
+	var b strings.Builder
+	sep := false
+	for _, r := range strings.ToLower(v) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if sep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			sep = false
+			continue
+		}
+		sep = true
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package sqlt
+
+import (
+	"testing"
+)
+
+// Unit test Partition.Name() and Partition.Bound()
+func TestPartitionNameAndBound(t *testing.T) {
+	cases := []struct {
+		p     Partition
+		name  string
+		bound string
+	}{
+		{Partition{parent: "events", from: "2024-01-01", to: "2024-02-01"},
+			"events_2024_01_01", "FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')"},
+		{Partition{parent: "events", from: "minvalue", to: "2024-01-01"},
+			"events_minvalue", "FOR VALUES FROM (MINVALUE) TO ('2024-01-01')"},
+		{Partition{parent: "sales.orders", values: []string{"EU", "UK"}},
+			"sales.orders_eu_uk", "FOR VALUES IN ('EU', 'UK')"},
+		{Partition{parent: "users", modulus: 4, remainder: 3},
+			"users_3_of_4", "FOR VALUES WITH (MODULUS 4, REMAINDER 3)"},
+		{Partition{parent: "users", isDefault: true},
+			"users_default", "DEFAULT"},
+		{Partition{name: "old_events", parent: "events", isDefault: true},
+			"old_events", "DEFAULT"},
+	}
+	for _, c := range cases {
+		if exp, act := c.name, c.p.Name(); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+		if exp, act := c.bound, c.p.Bound(); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+	}
+}
+
+// Unit test DetachPartitionTmpl
+func TestDetachPartitionTmpl(t *testing.T) {
+	p := Partition{name: "events_2024_01", parent: "events", concurrently: true}
+
+	exp := `ALTER TABLE events
+	DETACH PARTITION events_2024_01 CONCURRENTLY;`
+	act, err := ProcessTmpl(&p, DetachPartitionTmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test Table.PartitionBy() and Table.PrimaryKeyColumns()
+func TestTablePartitionBy(t *testing.T) {
+	tbl := Table{}
+	tbl.SetPartitionBy("RANGE", []string{"createdAt"})
+
+	if exp, act := "RANGE (created_at)", tbl.PartitionBy(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+	if exp, act := "id, created_at", tbl.PrimaryKeyList(); exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
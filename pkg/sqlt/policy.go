@@ -0,0 +1,105 @@
+package sqlt
+
+import (
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Policy is a row-level security policy on a table.
+type Policy struct {
+	name        string
+	tableName   string
+	restrictive bool
+	command     string
+	roles       []string
+	using       string
+	withCheck   string
+	force       bool
+}
+
+// Name ...
+func (p *Policy) Name() string {
+	return p.name
+}
+
+// SetName ...
+func (p *Policy) SetName(name string) {
+	p.name = strcase.ToSnake(name)
+}
+
+// TableName ...
+func (p *Policy) TableName() string {
+	return p.tableName
+}
+
+// SetTableName ...
+func (p *Policy) SetTableName(name string) {
+	p.tableName = qualifiedName(name)
+}
+
+// Restrictive reports whether the policy is restrictive. Restrictive
+// policies are combined with AND, and permissive policies with OR.
+func (p *Policy) Restrictive() bool {
+	return p.restrictive
+}
+
+// SetRestrictive ...
+func (p *Policy) SetRestrictive(restrictive bool) {
+	p.restrictive = restrictive
+}
+
+// Command returns the command that the policy applies to, e.g. `SELECT`. An
+// empty string means ALL.
+func (p *Policy) Command() string {
+	return p.command
+}
+
+// SetCommand sets the command after upcasing it. ALL is stored as an empty
+// string.
+func (p *Policy) SetCommand(command string) {
+	p.command = strings.ToUpper(command)
+	if p.command == "ALL" {
+		p.command = ""
+	}
+}
+
+// AddRole adds a role that the policy applies to.
+func (p *Policy) AddRole(name string) {
+	p.roles = append(p.roles, roleName(name))
+}
+
+// RoleList returns the roles joined by commas. An empty string means PUBLIC.
+func (p *Policy) RoleList() string {
+	return strings.Join(p.roles, ", ")
+}
+
+// Using returns the expression that existing rows must satisfy to be visible.
+func (p *Policy) Using() string {
+	return p.using
+}
+
+// SetUsing ...
+func (p *Policy) SetUsing(expr string) {
+	p.using = strings.TrimSpace(expr)
+}
+
+// WithCheck returns the expression that new rows must satisfy.
+func (p *Policy) WithCheck() string {
+	return p.withCheck
+}
+
+// SetWithCheck ...
+func (p *Policy) SetWithCheck(expr string) {
+	p.withCheck = strings.TrimSpace(expr)
+}
+
+// Force reports whether row-level security also applies to the table owner.
+func (p *Policy) Force() bool {
+	return p.force
+}
+
+// SetForce ...
+func (p *Policy) SetForce(force bool) {
+	p.force = force
+}
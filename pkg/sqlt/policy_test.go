@@ -0,0 +1,23 @@
+package sqlt
+
+import (
+	"testing"
+)
+
+// Unit test CreatePolicyTmpl
+func TestCreatePolicyTmpl(t *testing.T) {
+	p := Policy{name: "own_rows", tableName: "documents", restrictive: true}
+	p.SetCommand("ALL")
+	p.SetUsing("owner = current_user")
+
+	exp := `CREATE POLICY own_rows ON documents
+	AS RESTRICTIVE
+	USING (owner = current_user);`
+	act, err := ProcessTmpl(&p, CreatePolicyTmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
@@ -0,0 +1,68 @@
+package sqlt
+
+import (
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Role is a database role, i.e. a user or a group.
+type Role struct {
+	name    string
+	login   bool
+	inRoles []string
+}
+
+// Name ...
+func (r *Role) Name() string {
+	return r.name
+}
+
+// SetName ...
+func (r *Role) SetName(name string) {
+	r.name = roleName(name)
+}
+
+// Login reports whether the role may log in, i.e. whether it is a user.
+func (r *Role) Login() bool {
+	return r.login
+}
+
+// SetLogin ...
+func (r *Role) SetLogin(login bool) {
+	r.login = login
+}
+
+// InRoles returns the roles that the role is a member of.
+func (r *Role) InRoles() []string {
+	return r.inRoles
+}
+
+// AddInRole adds a role that the role is a member of.
+func (r *Role) AddInRole(name string) {
+	r.inRoles = append(r.inRoles, roleName(name))
+}
+
+// Options returns the options of a CREATE ROLE statement, e.g.
+// `LOGIN IN ROLE readers`. A password is never part of a migration; it should
+// be set outside of version control.
+func (r *Role) Options() string {
+	opts := make([]string, 0, 2)
+	if r.login {
+		opts = append(opts, "LOGIN")
+	}
+	if len(r.inRoles) > 0 {
+		opts = append(opts, "IN ROLE "+strings.Join(r.inRoles, ", "))
+	}
+	return strings.Join(opts, " ")
+}
+
+// roleName converts a role name to snake_case, except for the PUBLIC,
+// CURRENT_USER, CURRENT_ROLE and SESSION_USER keywords, which are upcased.
+func roleName(name string) string {
+	switch u := strings.ToUpper(name); u {
+	case "PUBLIC", "CURRENT_USER", "CURRENT_ROLE", "SESSION_USER":
+		return u
+	}
+	return strcase.ToSnake(name)
+}
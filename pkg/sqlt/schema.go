@@ -0,0 +1,43 @@
+package sqlt
+
+import (
+	"github.com/iancoleman/strcase"
+)
+
+// Schema ...
+type Schema struct {
+	name    string
+	owner   string
+	cascade bool
+}
+
+// Name ...
+func (s *Schema) Name() string {
+	return s.name
+}
+
+// SetName ...
+func (s *Schema) SetName(name string) {
+	s.name = strcase.ToSnake(name)
+}
+
+// Owner returns the role that owns the schema. An empty string means the
+// current user.
+func (s *Schema) Owner() string {
+	return s.owner
+}
+
+// SetOwner ...
+func (s *Schema) SetOwner(role string) {
+	s.owner = role
+}
+
+// Cascade reports whether dropping the schema also drops the objects in it.
+func (s *Schema) Cascade() bool {
+	return s.cascade
+}
+
+// SetCascade ...
+func (s *Schema) SetCascade(cascade bool) {
+	s.cascade = cascade
+}
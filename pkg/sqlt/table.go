@@ -1,12 +1,45 @@
 package sqlt
 
-import "github.com/iancoleman/strcase"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Primary key strategies supported by Table.
+const (
+	// BigserialPrimaryKey is an `id bigserial` column. It is the default.
+	BigserialPrimaryKey string = "bigserial"
+
+	// IdentityPrimaryKey is an `id bigint GENERATED ALWAYS AS IDENTITY` column.
+	IdentityPrimaryKey string = "identity"
+
+	// UUIDPrimaryKey is an `id uuid` column with a `gen_random_uuid()` default,
+	// which requires PostgreSQL 13 or the pgcrypto extension.
+	UUIDPrimaryKey string = "uuid"
+
+	// NoPrimaryKey omits the primary key.
+	NoPrimaryKey string = "none"
+
+	// CompositePrimaryKey is a primary key on a list of the table's columns.
+	CompositePrimaryKey string = "composite"
+)
 
 // Table ...
 type Table struct {
-	name    string
-	newName string
-	columns []Column
+	name              string
+	newName           string
+	columns           []Column
+	primaryKey        string
+	primaryKeyColumns []string
+	noTimestamps      bool
+	timestampTZ       bool
+	timestampDefault  bool
+	updatedAtTrigger  bool
+	partitionStrategy string
+	partitionKey      []string
+	comment           string
 }
 
 // Name ...
@@ -16,17 +49,23 @@ func (t *Table) Name() string {
 
 // SetName ...
 func (t *Table) SetName(name string) {
-	t.name = strcase.ToSnake(name)
+	t.name = qualifiedName(name)
 }
 
-// NewName ...
+// NewName returns the new name of a renamed table. A table cannot be moved to
+// another schema by renaming it, so NewName is not schema-qualified.
 func (t *Table) NewName() string {
-	return t.newName
+	return unqualifiedName(t.newName)
+}
+
+// QualifiedNewName returns NewName qualified by the schema of Name.
+func (t *Table) QualifiedNewName() string {
+	return schemaOf(t.Name()) + t.NewName()
 }
 
 // SetNewName ...
 func (t *Table) SetNewName(name string) {
-	t.newName = strcase.ToSnake(name)
+	t.newName = qualifiedName(name)
 }
 
 // Columns ...
@@ -43,3 +82,214 @@ func (t *Table) SetColumns(cols []Column) {
 func (t *Table) AddColumn(col Column) {
 	t.columns = append(t.columns, col)
 }
+
+// PrimaryKey returns the primary key strategy. The default is bigserial.
+func (t *Table) PrimaryKey() string {
+	if t.primaryKey == "" {
+		return BigserialPrimaryKey
+	}
+	return t.primaryKey
+}
+
+// SetPrimaryKey sets the primary key strategy after downcasing it.
+func (t *Table) SetPrimaryKey(strategy string) {
+	t.primaryKey = strings.ToLower(strategy)
+}
+
+// PrimaryKeyColumns returns the names of the primary key columns. The primary
+// key of a partitioned table must include the partition key columns, so they
+// are appended to the primary key columns.
+func (t *Table) PrimaryKeyColumns() []string {
+	var cols []string
+	switch t.PrimaryKey() {
+	case NoPrimaryKey:
+		return nil
+	case CompositePrimaryKey:
+		cols = append(cols, t.primaryKeyColumns...)
+	default:
+		cols = []string{"id"}
+	}
+
+	for _, k := range t.partitionKey {
+		found := false
+		for _, c := range cols {
+			found = found || c == k
+		}
+		if !found && !strings.HasPrefix(k, "(") {
+			cols = append(cols, k)
+		}
+	}
+
+	return cols
+}
+
+// SetPrimaryKeyColumns converts each column name to snake_case, then sets the
+// primary key columns and the composite primary key strategy.
+func (t *Table) SetPrimaryKeyColumns(names []string) {
+	t.primaryKey = CompositePrimaryKey
+	t.primaryKeyColumns = make([]string, 0, len(names))
+	for _, n := range names {
+		t.primaryKeyColumns = append(t.primaryKeyColumns, strcase.ToSnake(n))
+	}
+}
+
+// PrimaryKeyList returns the primary key columns formatted for a PRIMARY KEY
+// clause.
+func (t *Table) PrimaryKeyList() string {
+	return strings.Join(t.PrimaryKeyColumns(), ", ")
+}
+
+// PrimaryKeyColumnDefinition returns the definition of the `id` column for the
+// primary key strategy, or an empty string if the strategy has no `id` column.
+func (t *Table) PrimaryKeyColumnDefinition() string {
+	switch t.PrimaryKey() {
+	case BigserialPrimaryKey:
+		return "id bigserial NOT NULL"
+	case IdentityPrimaryKey:
+		return "id bigint GENERATED ALWAYS AS IDENTITY"
+	case UUIDPrimaryKey:
+		return "id uuid NOT NULL DEFAULT gen_random_uuid()"
+	}
+	return ""
+}
+
+// Definitions returns the PRIMARY KEY clause and the column definitions that
+// precede the timestamp columns in a CREATE TABLE statement.
+func (t *Table) Definitions() []string {
+	defs := make([]string, 0, len(t.columns)+2)
+	if l := t.PrimaryKeyList(); l != "" {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", l))
+	}
+	if d := t.PrimaryKeyColumnDefinition(); d != "" {
+		defs = append(defs, d)
+	}
+	for _, c := range t.columns {
+		defs = append(defs, fmt.Sprintf("%s %s", c.Name(), c.Type()))
+	}
+
+	return defs
+}
+
+// Timestamps reports whether the table has created_at and updated_at columns.
+// The default is true.
+func (t *Table) Timestamps() bool {
+	return !t.noTimestamps
+}
+
+// SetTimestamps ...
+func (t *Table) SetTimestamps(timestamps bool) {
+	t.noTimestamps = !timestamps
+}
+
+// TimestampTZ reports whether the timestamp columns are of type timestamptz.
+func (t *Table) TimestampTZ() bool {
+	return t.timestampTZ
+}
+
+// SetTimestampTZ ...
+func (t *Table) SetTimestampTZ(tz bool) {
+	t.timestampTZ = tz
+}
+
+// TimestampType returns the data type of the timestamp columns.
+func (t *Table) TimestampType() string {
+	if t.timestampTZ {
+		return "timestamp(6) with time zone"
+	}
+	return "timestamp(6) without time zone"
+}
+
+// TimestampDefault reports whether the timestamp columns default to now().
+func (t *Table) TimestampDefault() bool {
+	return t.timestampDefault
+}
+
+// SetTimestampDefault ...
+func (t *Table) SetTimestampDefault(d bool) {
+	t.timestampDefault = d
+}
+
+// UpdatedAtTrigger reports whether a trigger keeps the updated_at column
+// current.
+func (t *Table) UpdatedAtTrigger() bool {
+	return t.updatedAtTrigger
+}
+
+// SetUpdatedAtTrigger ...
+func (t *Table) SetUpdatedAtTrigger(trigger bool) {
+	t.updatedAtTrigger = trigger
+}
+
+// UpdatedAtTriggerName will generate a name for the trigger that keeps the
+// updated_at column current.
+func (t *Table) UpdatedAtTriggerName() string {
+	return identifier(unqualifiedName(t.Name())+"_updated_at", "_mnrk_trg")
+}
+
+// Comment returns the comment on the table.
+func (t *Table) Comment() string {
+	return t.comment
+}
+
+// SetComment ...
+func (t *Table) SetComment(comment string) {
+	t.comment = comment
+}
+
+// QuotedComment returns the comment on the table as a string literal, or an
+// empty string if the table has no comment.
+func (t *Table) QuotedComment() string {
+	if t.comment == "" {
+		return ""
+	}
+	return quoteLiteral(t.comment)
+}
+
+// PartitionStrategy returns the partitioning strategy of a partitioned table,
+// or an empty string if the table is not partitioned.
+func (t *Table) PartitionStrategy() string {
+	return t.partitionStrategy
+}
+
+// PartitionKey returns the columns and expressions that the table is
+// partitioned by.
+func (t *Table) PartitionKey() []string {
+	return t.partitionKey
+}
+
+// SetPartitionBy sets the partitioning strategy, after downcasing it, and the
+// partition key. Column names are converted to snake_case; an expression must
+// be enclosed in parentheses and is used as given.
+func (t *Table) SetPartitionBy(strategy string, key []string) {
+	t.partitionStrategy = strings.ToLower(strategy)
+	t.partitionKey = make([]string, 0, len(key))
+	for _, k := range key {
+		if !strings.HasPrefix(k, "(") {
+			k = strcase.ToSnake(k)
+		}
+		t.partitionKey = append(t.partitionKey, k)
+	}
+}
+
+// PartitionBy returns the PARTITION BY clause of a partitioned table without
+// its keywords, e.g. `RANGE (created_at)`, or an empty string if the table is
+// not partitioned.
+func (t *Table) PartitionBy() string {
+	if t.partitionStrategy == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s (%s)", strings.ToUpper(t.partitionStrategy), strings.Join(t.partitionKey, ", "))
+}
+
+// PrimaryKeyType returns the data type of a column that references an `id`
+// column created by the primary key strategy, or an empty string if the
+// strategy has no `id` column.
+func PrimaryKeyType(strategy string) string {
+	switch strings.ToLower(strategy) {
+	case "", BigserialPrimaryKey, IdentityPrimaryKey:
+		return "bigint"
+	case UUIDPrimaryKey:
+		return "uuid"
+	}
+	return ""
+}
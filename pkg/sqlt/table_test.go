@@ -180,3 +180,130 @@ func TestTableAddColumn(t *testing.T) {
 		}
 	}
 }
+
+// Unit test Table.PrimaryKeyColumns() and Table.PrimaryKeyColumnDefinition()
+func TestTablePrimaryKey(t *testing.T) {
+	cases := []struct {
+		strategy string
+		list     string
+		def      string
+	}{
+		{"", "id", "id bigserial NOT NULL"},
+		{"Identity", "id", "id bigint GENERATED ALWAYS AS IDENTITY"},
+		{"uuid", "id", "id uuid NOT NULL DEFAULT gen_random_uuid()"},
+		{"none", "", ""},
+	}
+	for _, c := range cases {
+		tbl := Table{}
+		tbl.SetPrimaryKey(c.strategy)
+
+		if exp, act := c.list, tbl.PrimaryKeyList(); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+		if exp, act := c.def, tbl.PrimaryKeyColumnDefinition(); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+	}
+}
+
+// Unit test Table.SetPrimaryKeyColumns()
+func TestTableSetPrimaryKeyColumns(t *testing.T) {
+	tbl := Table{}
+	tbl.SetPrimaryKeyColumns([]string{"userID", "groupID"})
+
+	exp := CompositePrimaryKey
+	act := tbl.PrimaryKey()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	exp = "user_id, group_id"
+	act = tbl.PrimaryKeyList()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	exp = ""
+	act = tbl.PrimaryKeyColumnDefinition()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test PrimaryKeyType()
+func TestPrimaryKeyType(t *testing.T) {
+	cases := [][]string{
+		{"", "bigint"},
+		{"bigserial", "bigint"},
+		{"identity", "bigint"},
+		{"UUID", "uuid"},
+		{"none", ""},
+	}
+	for _, c := range cases {
+		if exp, act := c[1], PrimaryKeyType(c[0]); exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+	}
+}
+
+// Unit test Table.TimestampType()
+func TestTableTimestampType(t *testing.T) {
+	tbl := Table{}
+
+	exp := "timestamp(6) without time zone"
+	act := tbl.TimestampType()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	tbl.SetTimestampTZ(true)
+	exp = "timestamp(6) with time zone"
+	act = tbl.TimestampType()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test Table.Definitions()
+func TestTableDefinitions(t *testing.T) {
+	tbl := Table{}
+	tbl.SetPrimaryKey(NoPrimaryKey)
+	tbl.AddColumn(Column{name: "name", colType: "text"})
+
+	exp := []string{"name text"}
+	act := tbl.Definitions()
+	if len(act) != 1 || exp[0] != act[0] {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test Table.UpdatedAtTriggerName()
+func TestTableUpdatedAtTriggerName(t *testing.T) {
+	tbl := Table{}
+	tbl.SetName("users")
+
+	exp := "users_updated_at_mnrk_trg"
+	act := tbl.UpdatedAtTriggerName()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test Table.SetName() and Table.SetNewName() with schema-qualified names.
+func TestTableQualifiedName(t *testing.T) {
+	tbl := Table{}
+	tbl.SetName("Billing.InvoiceItems")
+	tbl.SetNewName("billing.LineItems")
+
+	cases := [][]string{
+		{"billing.invoice_items", tbl.Name()},
+		{"line_items", tbl.NewName()},
+		{"billing.line_items", tbl.QualifiedNewName()},
+		{"invoice_items_updated_at_mnrk_trg", tbl.UpdatedAtTriggerName()},
+	}
+	for _, c := range cases {
+		if exp, act := c[0], c[1]; exp != act {
+			t.Errorf("want %q; got %q", exp, act)
+		}
+	}
+}
@@ -6,9 +6,9 @@ import (
 )
 
 // SQL templates for DATABASE operaions
-const (
+var (
 	// CreateDBTmpl is a SQL template for creating databases.
-	CreateDBTmpl string = `CREATE DATABASE {{.Name}} OWNER {{.Owner}};`
+	CreateDBTmpl string = `CREATE DATABASE {{.Name}} OWNER {{.Owner}}{{with .Options}} {{.}}{{end}};`
 
 	// CopyTableTmpl is a SQL template for copying databases.
 	CopyDBTmpl string = `CREATE DATABASE {{.CopyTargetName}} TEMPLATE {{.Name}};`
@@ -20,22 +20,142 @@ const (
 	RenameDBTmpl string = `ALTER DATABASE {{.Name}} RENAME TO {{.NewName}};`
 )
 
+// SQL templates for SCHEMA operations
+var (
+	// CreateSchemaTmpl is a SQL template for creating schemas.
+	CreateSchemaTmpl string = `CREATE SCHEMA IF NOT EXISTS {{.Name}}{{with .Owner}} AUTHORIZATION {{.}}{{end}};`
+
+	// DropSchemaTmpl is a SQL template for dropping schemas.
+	DropSchemaTmpl string = `DROP SCHEMA IF EXISTS {{.Name}}{{if .Cascade}} CASCADE{{end}};`
+)
+
+// SQL templates for PARTITION operations
+var (
+	// CreatePartitionsTmpl is a SQL template for creating several partitions of a partitioned
+	// table. It is applied to a []Partition.
+	CreatePartitionsTmpl string = `{{range $i, $p := .}}{{if $i}}
+
+{{end}}CREATE TABLE {{$p.Name}} PARTITION OF {{$p.Parent}}
+	{{$p.Bound}};{{end}}`
+
+	// DropPartitionsTmpl is a SQL template for dropping several partitions. It is applied to a
+	// []Partition, which should be in the reverse order of creation.
+	DropPartitionsTmpl string = `{{range $i, $p := .}}{{if $i}}
+{{end}}DROP TABLE IF EXISTS {{$p.Name}};{{end}}`
+
+	// AttachPartitionTmpl is a SQL template for attaching a table as a partition.
+	AttachPartitionTmpl string = `ALTER TABLE {{.Parent}}
+	ATTACH PARTITION {{.Name}} {{.Bound}};`
+
+	// DetachPartitionTmpl is a SQL template for detaching a partition. If Concurrently is true,
+	// the statement cannot be executed inside a transaction block.
+	DetachPartitionTmpl string = `ALTER TABLE {{.Parent}}
+	DETACH PARTITION {{.Name}}{{if .Concurrently}} CONCURRENTLY{{end}};`
+)
+
+// SQL templates for ROLE operations
+var (
+	// CreateRoleTmpl is a SQL template for creating roles.
+	CreateRoleTmpl string = `CREATE ROLE {{.Name}}{{with .Options}} {{.}}{{end}};`
+
+	// DropRoleTmpl is a SQL template for dropping roles.
+	DropRoleTmpl string = `DROP ROLE IF EXISTS {{.Name}};`
+)
+
+// SQL templates for GRANT operations
+var (
+	// GrantTmpl is a SQL template for granting privileges, or default privileges, to roles.
+	GrantTmpl string = `{{if .Defaults}}ALTER DEFAULT PRIVILEGES{{with .ForRole}} FOR ROLE {{.}}{{end}} IN SCHEMA {{.Schemas}}
+	{{end}}GRANT {{.PrivilegeList}} ON {{.Target}} TO {{.RoleList}}{{if .WithGrantOption}} WITH GRANT OPTION{{end}};`
+
+	// RevokeTmpl is a SQL template for revoking privileges, or default privileges, from roles.
+	RevokeTmpl string = `{{if .Defaults}}ALTER DEFAULT PRIVILEGES{{with .ForRole}} FOR ROLE {{.}}{{end}} IN SCHEMA {{.Schemas}}
+	{{end}}REVOKE {{.PrivilegeList}} ON {{.Target}} FROM {{.RoleList}};`
+)
+
+// SQL templates for ROW LEVEL SECURITY operations
+var (
+	// EnableRowLevelSecurityTmpl is a SQL template for enabling row-level security on a table.
+	EnableRowLevelSecurityTmpl string = `ALTER TABLE {{.TableName}} ENABLE ROW LEVEL SECURITY;{{if .Force}}
+ALTER TABLE {{.TableName}} FORCE ROW LEVEL SECURITY;{{end}}`
+
+	// DisableRowLevelSecurityTmpl is a SQL template for disabling row-level security on a table.
+	DisableRowLevelSecurityTmpl string = `{{if .Force}}ALTER TABLE {{.TableName}} NO FORCE ROW LEVEL SECURITY;
+{{end}}ALTER TABLE {{.TableName}} DISABLE ROW LEVEL SECURITY;`
+
+	// CreatePolicyTmpl is a SQL template for creating row-level security policies.
+	CreatePolicyTmpl string = `CREATE POLICY {{.Name}} ON {{.TableName}}
+	{{- if .Restrictive}}
+	AS RESTRICTIVE{{end}}
+	{{- with .Command}}
+	FOR {{.}}{{end}}
+	{{- with .RoleList}}
+	TO {{.}}{{end}}
+	{{- with .Using}}
+	USING ({{.}}){{end}}
+	{{- with .WithCheck}}
+	WITH CHECK ({{.}}){{end}};`
+
+	// DropPolicyTmpl is a SQL template for dropping row-level security policies.
+	DropPolicyTmpl string = `DROP POLICY IF EXISTS {{.Name}} ON {{.TableName}};`
+)
+
+// SQL templates for COMMENT operations
+var (
+	// CommentTmpl is a SQL template for setting or removing the comment on a table or column.
+	CommentTmpl string = `COMMENT ON {{.Object}} IS {{.Literal}};`
+)
+
+// SQL templates for EXTENSION operations
+var (
+	// CreateExtensionTmpl is a SQL template for creating extensions.
+	CreateExtensionTmpl string = `CREATE EXTENSION IF NOT EXISTS {{.QuotedName}}
+	{{- with .Schema}} SCHEMA {{.}}{{end}}
+	{{- with .QuotedVersion}} VERSION {{.}}{{end}};`
+
+	// DropExtensionTmpl is a SQL template for dropping extensions.
+	DropExtensionTmpl string = `DROP EXTENSION IF EXISTS {{.QuotedName}};`
+)
+
 // SQL templates for TABLE operaions
-const (
-	// CreateTableTmpl is a SQL template for creating tables.
+var (
+	// CreateTableTmpl is a SQL template for creating tables, which may be partitioned. If
+	// UpdatedAtTrigger is true, a trigger that calls set_updated_at() is created after the table.
+	// Comments on the table and its columns follow.
 	CreateTableTmpl string = `CREATE TABLE {{.Name}} (
-	PRIMARY KEY (id),
-	id bigserial NOT NULL,
-	{{- range .Columns}}
-	{{.Name}} {{.Type}},
-	{{- end}}
+	{{- range $i, $d := .Definitions}}{{if $i}},{{end}}
+	{{$d}}
+	{{- end}}{{if and .Timestamps .Definitions}},{{end}}
 
 	-- Specify additional fields here.
+	{{- if .Timestamps}}
 
 	-- Timestamps
-	created_at timestamp(6) without time zone NOT NULL,
-	updated_at timestamp(6) without time zone NOT NULL
-);`
+	created_at {{.TimestampType}} NOT NULL{{if .TimestampDefault}} DEFAULT now(){{end}},
+	updated_at {{.TimestampType}} NOT NULL{{if .TimestampDefault}} DEFAULT now(){{end}}
+	{{- end}}
+){{with .PartitionBy}} PARTITION BY {{.}}{{end}};{{if and .Timestamps .UpdatedAtTrigger}}
+
+CREATE TRIGGER {{.UpdatedAtTriggerName}}
+	BEFORE UPDATE ON {{.Name}}
+	FOR EACH ROW EXECUTE FUNCTION set_updated_at();{{end}}
+	{{- with .QuotedComment}}
+
+COMMENT ON TABLE {{$.Name}} IS {{.}};{{end}}` + columnCommentsTmpl
+
+	// CreateSetUpdatedAtFunctionTmpl is a SQL template for creating the trigger function shared
+	// by the triggers that keep updated_at columns current.
+	CreateSetUpdatedAtFunctionTmpl string = `CREATE OR REPLACE FUNCTION set_updated_at() RETURNS trigger
+	LANGUAGE plpgsql AS $$
+BEGIN
+	NEW.updated_at := now();
+	RETURN NEW;
+END;
+$$;`
+
+	// DropSetUpdatedAtFunctionTmpl is a SQL template for dropping the shared updated_at trigger
+	// function.
+	DropSetUpdatedAtFunctionTmpl string = `DROP FUNCTION IF EXISTS set_updated_at();`
 
 	// DropTableTmpl is a SQL template for dropping tables.
 	DropTableTmpl string = `DROP TABLE {{.Name}};`
@@ -45,7 +165,12 @@ const (
 
 	// AddColumnTmpl is a SQL template for adding columns to a table.
 	AddColumnTmpl string = `ALTER TABLE {{.Name}}{{range $i, $col := .Columns}}{{if $i}},{{end}}
-ADD COLUMN {{$col.Name}} {{$col.Type}}{{end}};`
+ADD COLUMN {{$col.Name}} {{$col.Type}}{{end}};` + columnCommentsTmpl
+
+	// columnCommentsTmpl is a SQL template fragment for the comments on the columns of a table.
+	columnCommentsTmpl string = `{{range $col := .Columns}}{{with $col.QuotedComment}}
+
+COMMENT ON COLUMN {{$.Name}}.{{$col.Name}} IS {{.}};{{end}}{{end}}`
 
 	// DropColumnTmpl is a SQL template for dropping columns from a table.
 	DropColumnTmpl string = `ALTER TABLE {{.Name}}{{$l := len .Columns}}{{range $i, $col := .Columns}}{{if $i}},{{end}}
@@ -64,32 +189,129 @@ RENAME COLUMN {{.Name}} TO {{.NewName}};
 	// CreateDefaultIndexTmpl is a SQL template for creating an index of the default type on one column.
 	CreateDefaultIndexTmpl string = `CREATE INDEX {{.Name}} ON {{.TableName}} ({{.ColumnName}});`
 
+	// CreateIndexTmpl is a SQL template for creating an index on one or more columns or
+	// expressions, with optional uniqueness, access method, covering columns and predicate. If
+	// Concurrently is true, the statement cannot be executed inside a transaction block.
+	CreateIndexTmpl string = `CREATE {{if .Unique}}UNIQUE {{end}}INDEX {{if .Concurrently}}CONCURRENTLY {{end}}{{.Name}} ON {{.TableName}}
+	{{- with .Method}} USING {{.}}{{end}} ({{.ColumnList}})
+	{{- if .Include}} INCLUDE ({{.IncludeList}}){{end}}
+	{{- with .Where}} WHERE {{.}}{{end}};`
+
 	// DropIndexTmpl is a SQL template for dropping and index.
-	DropIndexTmpl string = `DROP INDEX IF EXISTS {{.Name}};`
+	DropIndexTmpl string = `DROP INDEX {{if .Concurrently}}CONCURRENTLY {{end}}IF EXISTS {{.QualifiedName}};`
 
 	// AddForeignKeyTmpl is a SQL template for adding a foreign key column and a foreign key
-	// constraint to a table.
-	AddForeignKeyTmpl string = `ALTER TABLE {{.ReferencingTableName}}
-	ADD COLUMN {{.ReferencingColumnName}} {{.ReferencingColumnType}};
+	// constraint to a table. If ExistingColumn is true, only the constraint is added.
+	AddForeignKeyTmpl string = `{{if not .ExistingColumn}}ALTER TABLE {{.ReferencingTableName}}
+	ADD COLUMN {{.ReferencingColumnName}} {{.ReferencingColumnType}}{{if .NotNull}} NOT NULL{{end}};
 
-ALTER TABLE {{.ReferencingTableName}}
+{{end}}ALTER TABLE {{.ReferencingTableName}}
 	ADD CONSTRAINT {{.ConstraintName}} FOREIGN KEY ({{.ReferencingColumnName}})
-	REFERENCES {{.ReferencedTableName}} ({{.ReferencedColumnName}});`
+	REFERENCES {{.ReferencedTableName}} ({{.ReferencedColumnName}})
+	{{- with .OnDelete}}
+	ON DELETE {{.}}{{end}}
+	{{- with .OnUpdate}}
+	ON UPDATE {{.}}{{end}}
+	{{- if .Deferrable}}
+	DEFERRABLE INITIALLY DEFERRED{{end}}
+	{{- if .NotValid}}
+	NOT VALID{{end}};`
+
+	// ValidateForeignKeyTmpl is a SQL template for validating a foreign key constraint that was
+	// added as NOT VALID.
+	ValidateForeignKeyTmpl string = `ALTER TABLE {{.ReferencingTableName}}
+	VALIDATE CONSTRAINT {{.ConstraintName}};`
 
 	// DropForeignKeyTmpl is a SQL template for dropping a foreign key column and a foreign key
-	// constraint from a table.
+	// constraint from a table. If ExistingColumn is true, only the constraint is dropped.
 	DropForeignKeyTmpl string = `ALTER TABLE {{.ReferencingTableName}}
 	DROP CONSTRAINT IF EXISTS {{.ConstraintName}};
+{{- if not .ExistingColumn}}
 	
 ALTER TABLE {{.ReferencingTableName}}
-	DROP COLUMN IF EXISTS {{.ReferencingColumnName}};`
+	DROP COLUMN IF EXISTS {{.ReferencingColumnName}};{{end}}`
+
+	// AddConstraintTmpl is a SQL template for adding a CHECK, UNIQUE or EXCLUDE constraint to
+	// a table.
+	AddConstraintTmpl string = `ALTER TABLE {{.TableName}}
+	ADD CONSTRAINT {{.ConstraintName}} {{.Definition}}{{if .NotValid}} NOT VALID{{end}};`
+
+	// ValidateConstraintTmpl is a SQL template for validating a constraint that was added as
+	// NOT VALID.
+	ValidateConstraintTmpl string = `ALTER TABLE {{.TableName}}
+	VALIDATE CONSTRAINT {{.ConstraintName}};`
+
+	// DropConstraintTmpl is a SQL template for dropping a constraint from a table.
+	DropConstraintTmpl string = `ALTER TABLE {{.TableName}}
+	DROP CONSTRAINT IF EXISTS {{.ConstraintName}};`
+)
+
+// SQL templates for VIEW operations
+var (
+	// CreateViewTmpl is a SQL template for creating views and materialized views.
+	CreateViewTmpl string = `CREATE {{if .OrReplace}}OR REPLACE {{end}}{{if .Materialized}}MATERIALIZED {{end}}VIEW {{.Name}} AS
+{{.Query}}{{if .WithNoData}}
+WITH NO DATA{{end}};`
+
+	// DropViewTmpl is a SQL template for dropping views and materialized views.
+	DropViewTmpl string = `DROP {{if .Materialized}}MATERIALIZED {{end}}VIEW IF EXISTS {{.Name}};`
+
+	// RefreshMaterializedViewTmpl is a SQL template for refreshing materialized views.
+	RefreshMaterializedViewTmpl string = `REFRESH MATERIALIZED VIEW {{if .Concurrently}}CONCURRENTLY {{end}}{{.Name}};`
+)
+
+// SQL templates for FUNCTION and TRIGGER operations
+var (
+	// CreateFunctionTmpl is a SQL template for creating or replacing functions and procedures.
+	// The body is dollar-quoted with a tag that does not occur in the body.
+	CreateFunctionTmpl string = `CREATE OR REPLACE {{.Kind}} {{.Name}}({{.ArgumentList}})
+	{{- if not .Procedure}}
+	RETURNS {{.Returns}}{{end}}
+	LANGUAGE {{.Language}}
+AS {{.DollarTag}}
+{{.Body}}
+{{.DollarTag}};`
+
+	// DropFunctionTmpl is a SQL template for dropping functions and procedures.
+	DropFunctionTmpl string = `DROP {{.Kind}} IF EXISTS {{.Signature}};`
+
+	// CreateTriggerTmpl is a SQL template for creating triggers.
+	CreateTriggerTmpl string = `CREATE TRIGGER {{.TriggerName}}
+	{{.Timing}} {{.EventList}} ON {{.TableName}}
+	FOR EACH {{.ForEach}}{{with .When}} WHEN ({{.}}){{end}} EXECUTE FUNCTION {{.Function}}();`
+
+	// DropTriggerTmpl is a SQL template for dropping triggers.
+	DropTriggerTmpl string = `DROP TRIGGER IF EXISTS {{.TriggerName}} ON {{.TableName}};`
+)
+
+// SQL templates for TYPE operations
+var (
+	// CreateEnumTmpl is a SQL template for creating enum types.
+	CreateEnumTmpl string = `CREATE TYPE {{.Name}} AS ENUM ({{.ValueList}});`
+
+	// DropEnumTmpl is a SQL template for dropping enum types.
+	DropEnumTmpl string = `DROP TYPE IF EXISTS {{.Name}};`
+
+	// AddEnumValueTmpl is a SQL template for adding a value to an enum type. Before PostgreSQL
+	// 12, the statement cannot be executed inside a transaction block.
+	AddEnumValueTmpl string = `ALTER TYPE {{.Name}} ADD VALUE IF NOT EXISTS {{.Value}}
+	{{- with .Before}} BEFORE {{.}}{{end}}
+	{{- with .After}} AFTER {{.}}{{end}};`
+
+	// RenameEnumValueTmpl is a SQL template for renaming a value of an enum type.
+	RenameEnumValueTmpl string = `ALTER TYPE {{.Name}} RENAME VALUE {{.Value}} TO {{.NewValue}};`
 )
 
 // ProcessTmpl applies a data structure to a SQL template and returns a string.
+// A key that is missing from a map is an error.
 func ProcessTmpl(data interface{}, sqlt string) (string, error) {
 	// Initialize a template.
 	var s string
-	t := template.New("t")
+	name := "t"
+	if f, ok := templateFiles[templateName(sqlt)]; ok {
+		name = f // Name the template file in errors.
+	}
+	t := template.New(name).Option("missingkey=error")
 
 	// Parse the template.
 	t, err := t.Parse(sqlt)
@@ -45,16 +45,15 @@ func TestProcessTmpl(t *testing.T) {
 		Tmpl  string
 		SQL   string
 	}{
-		{Table{"users", "", []Column{}}, CreateTableTmpl, testCreateTableSQL},       // Create table
-		{Table{"users", "", []Column{}}, DropTableTmpl, testDropTableSQL},           // Drop table
-		{Table{"users", "people", []Column{}}, RenameTableTmpl, testRenameTableSQL}, // Rename table
+		{Table{name: "users", columns: []Column{}}, CreateTableTmpl, testCreateTableSQL},                    // Create table
+		{Table{name: "users", columns: []Column{}}, DropTableTmpl, testDropTableSQL},                        // Drop table
+		{Table{name: "users", newName: "people", columns: []Column{}}, RenameTableTmpl, testRenameTableSQL}, // Rename table
 		{ // Add column to table
 			Table{
-				"users",
-				"",
-				[]Column{
-					{"given_name", "", "VARCHAR"},
-					{"family_name", "", "VARCHAR"},
+				name: "users",
+				columns: []Column{
+					{"given_name", "", "VARCHAR", ""},
+					{"family_name", "", "VARCHAR", ""},
 				},
 			},
 			AddColumnTmpl,
@@ -62,10 +61,9 @@ func TestProcessTmpl(t *testing.T) {
 		},
 		{ // Drop column from table
 			Table{
-				"users",
-				"",
-				[]Column{
-					{"given_name", "", ""},
+				name: "users",
+				columns: []Column{
+					{"given_name", "", "", ""},
 				},
 			},
 			DropColumnTmpl,
@@ -73,11 +71,10 @@ func TestProcessTmpl(t *testing.T) {
 		},
 		{ // Rename column in table
 			Table{
-				"users",
-				"",
-				[]Column{
-					{"given_name", "first_name", ""},
-					{"family_name", "last_name", ""},
+				name: "users",
+				columns: []Column{
+					{"given_name", "first_name", "", ""},
+					{"family_name", "last_name", "", ""},
 				},
 			},
 			RenameColumnTmpl,
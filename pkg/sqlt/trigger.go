@@ -0,0 +1,149 @@
+package sqlt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Trigger ...
+type Trigger struct {
+	name      string
+	tableName string
+	function  string
+	timing    string
+	events    []TriggerEvent
+	forEach   string
+	when      string
+}
+
+// TriggerEvent is an event that fires a trigger. An UPDATE event may be
+// limited to a list of columns.
+type TriggerEvent struct {
+	event   string
+	columns []string
+}
+
+// Event ...
+func (e *TriggerEvent) Event() string {
+	return e.event
+}
+
+// SetEvent sets the event after upcasing it.
+func (e *TriggerEvent) SetEvent(event string) {
+	e.event = strings.ToUpper(event)
+}
+
+// Columns ...
+func (e *TriggerEvent) Columns() []string {
+	return e.columns
+}
+
+// AddColumn ...
+func (e *TriggerEvent) AddColumn(name string) {
+	e.columns = append(e.columns, strcase.ToSnake(name))
+}
+
+// Definition returns the event formatted for a CREATE TRIGGER statement.
+func (e *TriggerEvent) Definition() string {
+	if len(e.columns) == 0 {
+		return e.event
+	}
+	return fmt.Sprintf("%s OF %s", e.event, strings.Join(e.columns, ", "))
+}
+
+// Name ...
+func (t *Trigger) Name() string {
+	return t.name
+}
+
+// SetName ...
+func (t *Trigger) SetName(name string) {
+	t.name = strcase.ToSnake(name)
+}
+
+// TriggerName returns Name if it is set, or generates a name from TableName
+// and Function followed by a `_mnrk_trg` suffix.
+func (t *Trigger) TriggerName() string {
+	if t.name != "" {
+		return t.name
+	}
+	return identifier(unqualifiedName(t.TableName())+"_"+unqualifiedName(t.Function()), "_mnrk_trg")
+}
+
+// TableName ...
+func (t *Trigger) TableName() string {
+	return t.tableName
+}
+
+// SetTableName ...
+func (t *Trigger) SetTableName(name string) {
+	t.tableName = qualifiedName(name)
+}
+
+// Function returns the name of the trigger function.
+func (t *Trigger) Function() string {
+	return t.function
+}
+
+// SetFunction ...
+func (t *Trigger) SetFunction(name string) {
+	t.function = qualifiedName(name)
+}
+
+// Timing returns BEFORE, AFTER or INSTEAD OF. The default is BEFORE.
+func (t *Trigger) Timing() string {
+	if t.timing == "" {
+		return "BEFORE"
+	}
+	return t.timing
+}
+
+// SetTiming sets the timing after upcasing it.
+func (t *Trigger) SetTiming(timing string) {
+	t.timing = strings.ToUpper(timing)
+}
+
+// Events ...
+func (t *Trigger) Events() []TriggerEvent {
+	return t.events
+}
+
+// AddEvent ...
+func (t *Trigger) AddEvent(e TriggerEvent) {
+	t.events = append(t.events, e)
+}
+
+// EventList returns the events formatted for a CREATE TRIGGER statement.
+func (t *Trigger) EventList() string {
+	l := make([]string, 0, len(t.events))
+	for _, e := range t.events {
+		l = append(l, e.Definition())
+	}
+	return strings.Join(l, " OR ")
+}
+
+// ForEach returns ROW or STATEMENT. The default is ROW.
+func (t *Trigger) ForEach() string {
+	if t.forEach == "" {
+		return "ROW"
+	}
+	return t.forEach
+}
+
+// SetForEach sets ROW or STATEMENT after upcasing it.
+func (t *Trigger) SetForEach(level string) {
+	t.forEach = strings.ToUpper(level)
+}
+
+// When returns the condition that determines whether the trigger function is
+// executed.
+func (t *Trigger) When() string {
+	return t.when
+}
+
+// SetWhen ...
+func (t *Trigger) SetWhen(cond string) {
+	t.when = cond
+}
@@ -0,0 +1,38 @@
+package sqlt
+
+import (
+	"testing"
+)
+
+// Unit test Trigger.TriggerName()
+func TestTriggerName(t *testing.T) {
+	trg := Trigger{}
+	trg.SetTableName("posts")
+	trg.SetFunction("stampUser")
+
+	exp := "posts_stamp_user_mnrk_trg"
+	act := trg.TriggerName()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+
+	trg.SetName("stampPosts")
+	exp = "stamp_posts"
+	act = trg.TriggerName()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test Trigger.EventList()
+func TestTriggerEventList(t *testing.T) {
+	trg := Trigger{}
+	trg.AddEvent(TriggerEvent{event: "INSERT"})
+	trg.AddEvent(TriggerEvent{event: "UPDATE", columns: []string{"title", "body"}})
+
+	exp := "INSERT OR UPDATE OF title, body"
+	act := trg.EventList()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
@@ -0,0 +1,79 @@
+package sqlt
+
+import (
+	"strings"
+)
+
+// View ...
+type View struct {
+	name         string
+	query        string
+	materialized bool
+	withNoData   bool
+	orReplace    bool
+	concurrently bool
+}
+
+// Name ...
+func (v *View) Name() string {
+	return v.name
+}
+
+// SetName ...
+func (v *View) SetName(name string) {
+	v.name = qualifiedName(name)
+}
+
+// Query returns the SELECT query of the view.
+func (v *View) Query() string {
+	return v.query
+}
+
+// SetQuery sets the SELECT query of the view after trimming surrounding
+// whitespace and any trailing semicolons.
+func (v *View) SetQuery(query string) {
+	v.query = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(query), ";"))
+}
+
+// Materialized ...
+func (v *View) Materialized() bool {
+	return v.materialized
+}
+
+// SetMaterialized ...
+func (v *View) SetMaterialized(materialized bool) {
+	v.materialized = materialized
+}
+
+// WithNoData reports whether a materialized view is created without being
+// populated.
+func (v *View) WithNoData() bool {
+	return v.materialized && v.withNoData
+}
+
+// SetWithNoData ...
+func (v *View) SetWithNoData(noData bool) {
+	v.withNoData = noData
+}
+
+// OrReplace reports whether a view replaces an existing view of the same
+// name. A materialized view cannot be replaced.
+func (v *View) OrReplace() bool {
+	return !v.materialized && v.orReplace
+}
+
+// SetOrReplace ...
+func (v *View) SetOrReplace(orReplace bool) {
+	v.orReplace = orReplace
+}
+
+// Concurrently reports whether a materialized view is refreshed without
+// locking out concurrent selects. It requires a unique index on the view.
+func (v *View) Concurrently() bool {
+	return v.concurrently
+}
+
+// SetConcurrently ...
+func (v *View) SetConcurrently(concurrently bool) {
+	v.concurrently = concurrently
+}
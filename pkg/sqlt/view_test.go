@@ -0,0 +1,47 @@
+package sqlt
+
+import (
+	"testing"
+)
+
+// Unit test View.SetQuery()
+func TestViewSetQuery(t *testing.T) {
+	v := View{}
+	v.SetQuery("\n  SELECT * FROM users ;;\n")
+
+	exp := "SELECT * FROM users"
+	act := v.Query()
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
+
+// Unit test View.OrReplace() and View.WithNoData()
+func TestViewOptions(t *testing.T) {
+	v := View{}
+	v.SetOrReplace(true)
+	v.SetWithNoData(true)
+
+	if !v.OrReplace() || v.WithNoData() {
+		t.Error("want OR REPLACE and no WITH NO DATA for a view")
+	}
+
+	v.SetMaterialized(true)
+	if v.OrReplace() || !v.WithNoData() {
+		t.Error("want WITH NO DATA and no OR REPLACE for a materialized view")
+	}
+}
+
+// Unit test CreateViewTmpl
+func TestCreateViewTmpl(t *testing.T) {
+	v := View{name: "active_users", query: "SELECT * FROM users WHERE active", orReplace: true}
+
+	exp := "CREATE OR REPLACE VIEW active_users AS\nSELECT * FROM users WHERE active;"
+	act, err := ProcessTmpl(&v, CreateViewTmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp != act {
+		t.Errorf("want %q; got %q", exp, act)
+	}
+}
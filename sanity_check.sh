@@ -12,27 +12,81 @@ fi
 go install .
 
 # Remove any leftover migrations and reset the database.
-rm -rf migrations
-monarch db reset
+rm -rf migrations seeds templates
+monarch db reset --force
 
 # Generate migrations
+monarch g m create extension citext
 monarch g m create table users givenName:varchar familyName:varchar
 monarch g m add column users email:varchar phone:varchar
 monarch g m rename column users givenName:firstName familyName:lastName
 monarch g m drop column users phone
 monarch g m rename table users people
-monarch g m create table cars
+monarch g m create enum CarColor red green blue
+monarch g m add enum-value CarColor black --before red
+monarch g m create table cars --timestamp-default --updated-at-trigger
 monarch g m add foreignkey cars people
 monarch g m add column cars make:varchar modelYear:smallint modelName:text color:text
 monarch g m recast column cars modelName:varchar color:varchar
 monarch g m create index cars make
+monarch g m create view red_cars "SELECT * FROM cars WHERE color = 'red'"
+monarch g m create materialized view car_makes "SELECT make, count(*) FROM cars GROUP BY make" --unique-index make
+monarch g m refresh materialized view car_makes --concurrently
+monarch g m drop materialized view car_makes
+monarch g m drop view red_cars
+monarch g m create function touchCar --returns trigger
+monarch g m create trigger cars touchCar insert update:make
+monarch g m drop trigger cars touchCar insert update:make
+monarch g m drop function touchCar
+monarch g m create schema billing
+monarch g m create table billing.invoices total:numeric
+monarch g m create index billing.invoices total
+monarch g m drop table billing.invoices
+monarch g m drop schema billing
+monarch g m create table events --partition-by "range(created_at)"
+monarch g m create partitions events --monthly 3 --start 2024-01
+monarch g m create partition events --default
+monarch g m detach partition events events_default --default
+monarch g m attach partition events events_default --default
+monarch g m drop table events
+monarch g m comment table cars "Cars that people own"
+monarch g m comment column cars make "Manufacturer of the car"
+monarch g m create role monarchReader
+monarch g m grant table cars select --to monarchReader
+monarch g m grant default-privileges public select --to monarchReader
+monarch g m enable row-level-security cars
+monarch g m create policy cars readAll --for select --to monarchReader --using true
+monarch g m drop policy cars readAll --for select --to monarchReader --using true
+monarch g m disable row-level-security cars
+monarch g m revoke default-privileges public select --to monarchReader
+monarch g m revoke table cars select --to monarchReader
+monarch g m drop role monarchReader
+monarch g m add constraint cars check model_year_range "model_year > 1885"
+monarch g m add constraint cars unique make model_name model_year
+monarch g m drop constraint cars unique make model_name model_year
 monarch g m drop foreignkey cars people
 monarch g m drop table people
 monarch g m drop table cars
+monarch g m drop enum CarColor red green blue
+
+# Add a repeatable migration.
+echo "CREATE OR REPLACE VIEW car_colors AS SELECT DISTINCT color FROM cars;" > migrations/R_create_view_car_colors.sql
 
 # Migrate schemas.
 monarch db migrate
+monarch db prepare
+monarch db doctor
+
+# Seed the database.
+monarch g seed Roles
+monarch g seed Statuses --repeatable
+monarch db seed
+monarch db seed
+
+# Eject and list the SQL templates.
+monarch g templates --eject
+monarch g templates
 
 # Do cleanup.
-monarch db drop
-rm -rf migrations
+monarch db drop --force
+rm -rf migrations seeds templates